@@ -2,16 +2,24 @@ package core
 
 import (
 	"container/heap"
+	"context"
 	"fmt"
+	"math/rand/v2"
+	"strconv"
 	"sync/atomic"
 	"time"
 )
 
+// * arg 建議使用 Option（WithName、WithTimeout…）
+// ! 直接傳入 string、time.Duration、func() 等型別已棄用，保留相容
 func (c *cron) Add(spec string, action interface{}, arg ...interface{}) (int64, error) {
 	schedule, err := c.parser.parse(spec)
 	if err != nil {
 		return 0, fmt.Errorf("failed to parse: %w", err)
 	}
+	if err := c.checkAt(schedule); err != nil {
+		return 0, err
+	}
 
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -19,8 +27,12 @@ func (c *cron) Add(spec string, action interface{}, arg ...interface{}) (int64,
 	entry := &task{
 		ID:       atomic.AddInt64(&c.next, 1),
 		schedule: schedule,
+		spec:     spec,
 		enable:   true,
+		paused:   c.paused,
 		state:    TaskPending,
+		misfire:  c.misfire,
+		consumed: make(map[int64]int),
 	}
 
 	withError := false
@@ -28,7 +40,7 @@ func (c *cron) Add(spec string, action interface{}, arg ...interface{}) (int64,
 	switch v := action.(type) {
 	// * 無返回錯誤值
 	case func():
-		entry.action = func() error {
+		entry.action = func(context.Context) error {
 			v()
 			return nil
 		}
@@ -37,46 +49,105 @@ func (c *cron) Add(spec string, action interface{}, arg ...interface{}) (int64,
 	// * 有返回錯誤值
 	case func() error:
 		// * 標記有回傳值
+		withError = true
+		entry.action = func(context.Context) error {
+			return v()
+		}
+	// * 可接收取消訊號
+	case func(context.Context) error:
 		withError = true
 		entry.action = v
 	default:
-		return 0, fmt.Errorf("action need to be func() or func()")
+		return 0, fmt.Errorf("action need to be func(), func() error or func(context.Context) error")
 	}
 
-	var after []Wait
+	var location *time.Location
+	var dst *DSTPolicy
 	for _, e := range arg {
 		switch v := e.(type) {
+		case Option:
+			if err := v(entry); err != nil {
+				return 0, fmt.Errorf("invalid option: %w", err)
+			}
 		case string:
 			entry.description = v
+		// * 任務時區
+		case *time.Location:
+			location = v
+		// * 任務的夏令時間處理方式
+		case DSTPolicy:
+			dst = &v
+		// * 重複執行的處理方式
+		case OverlapPolicy:
+			entry.overlap = v
+		// * 錯過排程的處理方式
+		case Misfire:
+			entry.misfire = v
+		// * 失敗重試
+		case Retry:
+			entry.retry = v
 		case time.Duration:
 			entry.delay = v
 		case func():
 			entry.onDelay = v
 		// * 依賴任務
 		case []Wait:
-			after = append(after, v...)
-			entry.state = TaskPending
+			entry.after = append(entry.after, v...)
 		// ! Deprecated in v2.*.*
 		case []int64:
 			for _, id := range v {
-				after = append(after, Wait{ID: id})
+				entry.after = append(entry.after, Wait{ID: id})
+			}
+		default:
+			return 0, fmt.Errorf("unsupported argument type: %T", e)
+		}
+	}
+
+	if err := entry.validateWindow(); err != nil {
+		return 0, err
+	}
+
+	entry.withError = withError
+	if len(entry.after) > 0 {
+		if !withError {
+			return 0, fmt.Errorf("need return value to get dependence support")
+		}
+		if !entry.deferCheck {
+			if err := c.checkDependencies(entry.ID, entry.after); err != nil {
+				return 0, err
 			}
-			entry.state = TaskPending
 		}
+		entry.state = TaskPending
+		entry.wait = dependWait(entry.after)
 	}
 
-	if !withError && after != nil {
-		return 0, fmt.Errorf("need return value to get dependence support")
+	if result, ok := schedule.(*scheduleResult); ok {
+		// * 表達式的 CRON_TZ= 前綴優先於任務時區
+		if location != nil && result.location == nil {
+			result.location = location
+		}
+		if dst != nil {
+			result.dst = *dst
+		}
 	}
 
-	if after != nil {
-		entry.after = make([]Wait, len(after))
-		copy(entry.after, after)
+	if err := c.seed(entry); err != nil {
+		return 0, fmt.Errorf("failed to parse: %w", err)
 	}
 
+	entry.ctx, entry.cancel = context.WithCancel(context.Background())
+
+	c.tasks[entry.ID] = entry
+
 	if c.running {
 		c.add <- entry
 	} else {
+		// * 啟用錯過判定時以加入時間起算，延後啟動視為錯過
+		if entry.misfire.Threshold > 0 {
+			entry.mutex.Lock()
+			entry.next = entry.nextRun(c.clock.Now().In(c.location))
+			entry.mutex.Unlock()
+		}
 		c.heap = append(c.heap, entry)
 		heap.Init(&c.heap)
 		c.depend.manager.add(entry)
@@ -84,3 +155,62 @@ func (c *cron) Add(spec string, action interface{}, arg ...interface{}) (int64,
 
 	return entry.ID, nil
 }
+
+// * 於指定時間執行一次，執行完畢後自動移出排程
+func (c *cron) AddAt(at time.Time, action interface{}, opts ...Option) (int64, error) {
+	arg := make([]interface{}, len(opts))
+	for i, opt := range opts {
+		arg[i] = opt
+	}
+	return c.Add("@at "+at.Format(time.RFC3339Nano), action, arg...)
+}
+
+// * 於指定時間後執行一次
+func (c *cron) AddAfter(delay time.Duration, action interface{}, opts ...Option) (int64, error) {
+	if delay <= 0 {
+		return 0, fmt.Errorf("delay must be positive")
+	}
+	return c.AddAt(c.clock.Now().Add(delay), action, opts...)
+}
+
+// * @at 的時間需晚於目前時間，否則永遠不會執行
+func (c *cron) checkAt(s schedule) error {
+	if at, ok := s.(atScheduleResult); ok && !at.at.After(c.clock.Now()) {
+		return fmt.Errorf("time is in the past: %s", at.at.Format(time.RFC3339Nano))
+	}
+	return nil
+}
+
+// * 依賴等待逾時取各依賴中最長者
+func dependWait(after []Wait) time.Duration {
+	var wait time.Duration
+	for _, w := range after {
+		wait = max(wait, w.Delay)
+	}
+	return wait
+}
+
+// * H 欄位與隨機延遲以任務名稱為種子，未命名時以 ID 代替
+func (c *cron) seed(t *task) error {
+	key := t.name
+	if key == "" {
+		key = strconv.FormatInt(t.ID, 10)
+	}
+	t.random = rand.New(rand.NewPCG(hashKey(key), 0))
+
+	result, ok := t.schedule.(*scheduleResult)
+	if !ok || !result.hashed {
+		return nil
+	}
+	p := c.parser
+	p.hash = key
+	schedule, err := p.parse(t.spec)
+	if err != nil {
+		return err
+	}
+	// * 保留已套用的時區與夏令時間設定
+	hashed := schedule.(*scheduleResult)
+	hashed.location, hashed.dst = result.location, result.dst
+	t.schedule = hashed
+	return nil
+}
@@ -0,0 +1,192 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{timer: time.AfterFunc(d, f)}
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t realTimer) Reset(d time.Duration) bool {
+	return t.timer.Reset(d)
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	f := &FakeClock{now: now}
+	f.cond = sync.NewCond(&f.mutex)
+	return f
+}
+
+func (f *FakeClock) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.now
+}
+
+func (f *FakeClock) NewTimer(d time.Duration) Timer {
+	return f.addTimer(d, nil)
+}
+
+func (f *FakeClock) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.addTimer(d, fn)
+}
+
+// * 推進時間並依序觸發到期的 timer
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mutex.Lock()
+	f.now = f.now.Add(d)
+	now := f.now
+
+	sort.SliceStable(f.timers, func(i, j int) bool {
+		return f.timers[i].when.Before(f.timers[j].when)
+	})
+
+	var due []*fakeTimer
+	pending := f.timers[:0]
+	for _, t := range f.timers {
+		if t.when.After(now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.active = false
+		due = append(due, t)
+	}
+	f.timers = pending
+	f.cond.Broadcast()
+	f.mutex.Unlock()
+
+	for _, t := range due {
+		if t.fn != nil {
+			t.fn()
+			continue
+		}
+		select {
+		case t.c <- now:
+		default:
+		}
+	}
+}
+
+// * 設定至指定時間，早於目前時間時不動作
+func (f *FakeClock) Set(t time.Time) {
+	if d := t.Sub(f.Now()); d > 0 {
+		f.Advance(d)
+	}
+}
+
+// * 等待至少 n 個 timer 等待觸發，用於確認排程已進入等待狀態
+func (f *FakeClock) BlockUntil(n int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for len(f.timers) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *FakeClock) addTimer(d time.Duration, fn func()) *fakeTimer {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	t := &fakeTimer{
+		clock: f,
+		fn:    fn,
+	}
+	if fn == nil {
+		t.c = make(chan time.Time, 1)
+	}
+	f.schedule(t, d)
+	return t
+}
+
+func (f *FakeClock) schedule(t *fakeTimer, d time.Duration) {
+	t.when = f.now.Add(d)
+	// * 與 time.NewTimer 相同，未來時間小於等於零時立即觸發
+	if d <= 0 {
+		t.active = false
+		if t.fn != nil {
+			go t.fn()
+			return
+		}
+		select {
+		case t.c <- f.now:
+		default:
+		}
+		return
+	}
+	t.active = true
+	f.timers = append(f.timers, t)
+	f.cond.Broadcast()
+}
+
+func (f *FakeClock) remove(t *fakeTimer) bool {
+	if !t.active {
+		return false
+	}
+	t.active = false
+	for i, e := range f.timers {
+		if e == t {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			break
+		}
+	}
+	f.cond.Broadcast()
+	return true
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	active := t.clock.remove(t)
+	t.clock.schedule(t, d)
+	return active
+}
+
+// * 依時間來源建立逾時 context；系統時間沿用 context.WithTimeout
+func withTimeout(clock Clock, parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(realClock); ok {
+		return context.WithTimeout(parent, d)
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+	timer := clock.AfterFunc(d, func() {
+		cancel(context.DeadlineExceeded)
+	})
+	return ctx, func() {
+		timer.Stop()
+		cancel(context.Canceled)
+	}
+}
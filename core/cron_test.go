@@ -4,9 +4,13 @@
 package core
 
 import (
+	"container/heap"
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -144,6 +148,12 @@ func TestCron_Add(t *testing.T) {
 			action:  func() error { return nil },
 			wantErr: false,
 		},
+		{
+			name:    "valid func(context.Context) error task",
+			spec:    "@every 30s",
+			action:  func(ctx context.Context) error { return nil },
+			wantErr: false,
+		},
 		{
 			name:    "valid task with description",
 			spec:    "@every 30s",
@@ -201,6 +211,44 @@ func TestCron_Add(t *testing.T) {
 			args:    []interface{}{[]Wait{{ID: 1, State: Skip}}},
 			wantErr: false,
 		},
+		{
+			name:   "task with options",
+			spec:   "@every 30s",
+			action: func() error { return nil },
+			args: []interface{}{
+				WithName("report"),
+				WithDescription("daily report"),
+				WithTimeout(5 * time.Second),
+				WithOnTimeout(func() {}),
+				WithDependencies(Wait{ID: 1}),
+				WithOverlap(OverlapSkip),
+			},
+			wantErr: false,
+		},
+		{
+			name:     "invalid option",
+			spec:     "@every 30s",
+			action:   func() error { return nil },
+			args:     []interface{}{WithTimeout(0)},
+			wantErr:  true,
+			errorMsg: "timeout must be positive",
+		},
+		{
+			name:     "unsupported argument",
+			spec:     "@every 30s",
+			action:   func() {},
+			args:     []interface{}{42},
+			wantErr:  true,
+			errorMsg: "unsupported argument type: int",
+		},
+		{
+			name:     "func() with dependency option should fail",
+			spec:     "@every 30s",
+			action:   func() {},
+			args:     []interface{}{WithDependencies(Wait{ID: 1})},
+			wantErr:  true,
+			errorMsg: "need return value to get dependence support",
+		},
 	}
 
 	for _, tt := range tests {
@@ -337,11 +385,11 @@ func TestCron_List(t *testing.T) {
 		task := tasks[i]
 		if task.ID == id1 {
 			foundTask1 = true
-			assert.Equal(t, "Task 1", task.description)
+			assert.Equal(t, "Task 1", task.Description)
 		}
 		if task.ID == id2 {
 			foundTask2 = true
-			assert.Equal(t, "Task 2", task.description)
+			assert.Equal(t, "Task 2", task.Description)
 		}
 	}
 	assert.True(t, foundTask1, "Task 1 should be in list")
@@ -396,6 +444,72 @@ func TestCron_RemoveAll(t *testing.T) {
 	assert.Len(t, tasks, 0, "Should have 0 tasks after RemoveAll")
 }
 
+// TestCron_RemoveRunning 測試移除已離開 heap 的執行中任務
+func TestCron_RemoveRunning(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		add  func(c *cron, action func(context.Context) error) (int64, error)
+	}{
+		{"one-shot", func(c *cron, action func(context.Context) error) (int64, error) {
+			return c.AddAfter(time.Minute, action)
+		}},
+		{"last run", func(c *cron, action func(context.Context) error) (int64, error) {
+			return c.Add("@every 1m", action, WithMaxRuns(1))
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, clock := createFakeClockCron(t)
+			defer cleanupCron(t, c)
+
+			started := make(chan struct{})
+			cancelled := make(chan struct{})
+			id, err := tt.add(c, func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				close(cancelled)
+				return ctx.Err()
+			})
+			require.NoError(t, err)
+			c.Start()
+			clock.BlockUntil(1)
+			clock.Advance(time.Minute)
+			<-started
+
+			c.Remove(id)
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				t.Fatal("running task was not cancelled")
+			}
+			_, ok := c.Get(id)
+			assert.False(t, ok)
+		})
+	}
+}
+
+// TestCron_RemoveDependency 測試移除依賴任務後，依賴者立即失敗而非等到逾時
+func TestCron_RemoveDependency(t *testing.T) {
+	c, _ := createFakeClockCron(t)
+	defer cleanupCron(t, c)
+
+	parentID, err := c.Add("@daily", func() error { return nil })
+	require.NoError(t, err)
+	childID, err := c.Add("@daily", func() error { return nil }, WithDependencies(Wait{ID: parentID}))
+	require.NoError(t, err)
+	c.Start()
+
+	c.Remove(parentID)
+	done, err := c.RunNow(childID)
+	require.NoError(t, err)
+	select {
+	case result := <-done:
+		assert.Equal(t, TaskFailed, result.Status)
+		assert.ErrorContains(t, result.Error, "not found")
+	case <-time.After(time.Second):
+		t.Fatal("dependent waited for a removed task")
+	}
+}
+
 // TestCron_TaskExecution 測試任務執行（優化為更快的測試）
 func TestCron_TaskExecution(t *testing.T) {
 	if testing.Short() {
@@ -995,5 +1109,2474 @@ func TestCron_FastExecution(t *testing.T) {
 	// 驗證任務已添加到列表
 	tasks := c.List()
 	assert.Len(t, tasks, 1)
-	assert.Equal(t, "fast test", tasks[0].description)
+	assert.Equal(t, "fast test", tasks[0].Description)
+}
+
+// TestCron_ContextAction 測試 context 任務於逾時、移除與停止時取消
+func TestCron_ContextAction(t *testing.T) {
+	c := createTestCron(t)
+	defer cleanupCron(t, c)
+
+	cancelled := make(chan error, 1)
+	action := func(ctx context.Context) error {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return ctx.Err()
+	}
+
+	id, err := c.Add("@every 30s", action, "context task", 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.Greater(t, id, int64(0))
+
+	entry := c.heap[0]
+
+	t.Run("timeout", func(t *testing.T) {
+		err := entry.execute(context.Background(), realClock{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		select {
+		case err := <-cancelled:
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		case <-time.After(time.Second):
+			t.Fatal("Action context should be cancelled on timeout")
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- entry.execute(ctx, realClock{})
+		}()
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("Action should stop when scheduler context is cancelled")
+		}
+		<-cancelled
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		done := make(chan error, 1)
+		go func() {
+			done <- entry.execute(context.Background(), realClock{})
+		}()
+		c.Remove(id)
+
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("Action should stop when task is removed")
+		}
+		<-cancelled
+	})
+}
+
+// TestCron_SecondsField 測試 6 欄位秒級 cron 表達式
+func TestCron_SecondsField(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 10, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		seconds bool
+		spec    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:    "6-field with seconds enabled",
+			seconds: true,
+			spec:    "30 * * * * *",
+			want:    time.Date(2026, 1, 1, 0, 0, 30, 0, time.UTC),
+		},
+		{
+			name:    "6-field step seconds",
+			seconds: true,
+			spec:    "*/15 * * * * *",
+			want:    time.Date(2026, 1, 1, 0, 0, 15, 0, time.UTC),
+		},
+		{
+			name:    "5-field still accepted with seconds enabled",
+			seconds: true,
+			spec:    "1 0 * * *",
+			want:    time.Date(2026, 1, 1, 0, 1, 0, 0, time.UTC),
+		},
+		{
+			name:    "6-field rejected without seconds",
+			seconds: false,
+			spec:    "30 * * * * *",
+			wantErr: true,
+		},
+		{
+			name:    "sub-minute @every with seconds enabled",
+			seconds: true,
+			spec:    "@every 5s",
+			want:    base.Add(5 * time.Second),
+		},
+		{
+			name:    "sub-minute @every below floor",
+			seconds: false,
+			spec:    "@every 5s",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parser{seconds: tt.seconds}.parse(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.next(base))
+		})
+	}
+}
+
+// TestCron_SpecialDayCharacters 測試 L、W、#、? 特殊字元
+func TestCron_SpecialDayCharacters(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"last day of month", "0 0 L * *", date(2026, 2, 1), date(2026, 2, 28)},
+		{"last day of month in leap year", "0 0 L * *", date(2028, 2, 1), date(2028, 2, 29)},
+		{"nearest weekday before saturday", "0 0 15W * *", date(2026, 8, 1), date(2026, 8, 14)},
+		{"nearest weekday after sunday", "0 0 15W * *", date(2026, 2, 1), date(2026, 2, 16)},
+		{"nearest weekday does not cross month", "0 0 1W * *", date(2026, 7, 31), date(2026, 8, 3)},
+		{"last weekday of month", "0 0 LW * *", date(2026, 2, 1), date(2026, 2, 27)},
+		{"third friday", "0 0 ? * 5#3", date(2026, 1, 1), date(2026, 1, 16)},
+		{"last friday", "0 0 ? * 5L", date(2026, 1, 1), date(2026, 1, 30)},
+		{"list with last day", "0 0 L,15 * *", date(2026, 1, 16), date(2026, 1, 31)},
+		{"question mark in day of month", "0 0 ? * 1", date(2026, 1, 1), date(2026, 1, 5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parser{}.parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.next(tt.from))
+		})
+	}
+
+	invalid := []string{
+		"0 0 32W * *",
+		"0 0 * * 5#6",
+		"0 0 * * 8L",
+		"0 0 W * *",
+		"0 0 L,* * *",
+	}
+	for _, spec := range invalid {
+		t.Run(fmt.Sprintf("invalid_%s", spec), func(t *testing.T) {
+			_, err := parser{}.parse(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestCron_FieldNames 測試月份與星期名稱
+func TestCron_FieldNames(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"single month", "0 9 1 MAR *", time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{"month range", "0 9 1 APR-JUN *", time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)},
+		{"weekday range", "0 9 * JAN-MAR MON-FRI", time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)},
+		{"lowercase list", "0 9 * * sat,sun", time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC)},
+		{"mixed case", "0 9 * * Wed", time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 9 * * 7", time.Date(2026, 1, 4, 9, 0, 0, 0, time.UTC)},
+		{"range ending at 7", "0 9 * * 6-7", time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC)},
+		{"name with nth", "0 9 ? * FRI#2", time.Date(2026, 1, 9, 9, 0, 0, 0, time.UTC)},
+		{"sunday as 7 with last", "0 9 ? * 7L", time.Date(2026, 1, 25, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parser{}.parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.next(from))
+		})
+	}
+
+	invalid := []string{
+		"0 9 * JANUARY *",
+		"0 9 * * MONDAY",
+		"0 9 * FOO *",
+		"0 9 * * 8",
+	}
+	for _, spec := range invalid {
+		t.Run(fmt.Sprintf("invalid_%s", spec), func(t *testing.T) {
+			_, err := parser{}.parse(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestCron_StepExpansion 測試步進展開為實際值
+func TestCron_StepExpansion(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		min   int
+		max   int
+		want  []int
+	}{
+		{"month step starts at 1", "*/2", 1, 12, []int{1, 3, 5, 7, 9, 11}},
+		{"minute step starts at 0", "*/20", 0, 59, []int{0, 20, 40}},
+		{"ranged step", "10-50/5", 0, 59, []int{10, 15, 20, 25, 30, 35, 40, 45, 50}},
+		{"offset step", "5/15", 0, 59, []int{5, 20, 35, 50}},
+		{"list with ranged step", "1-10/2,20", 0, 59, []int{1, 3, 5, 7, 9, 20}},
+		{"list is sorted and unique", "30,1-5/2,3", 0, 59, []int{1, 3, 5, 30}},
+		{"day of month step", "*/10", 1, 31, []int{1, 11, 21, 31}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := parseField(tt.field, tt.min, tt.max)
+			require.NoError(t, err)
+			assert.False(t, field.All)
+			assert.Equal(t, tt.want, field.Values)
+		})
+	}
+
+	t.Run("named ranged step", func(t *testing.T) {
+		s, err := parser{}.parse("0 0 1 JAN-JUN/2 *")
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 3, 5}, s.(*scheduleResult).month.Values)
+	})
+
+	t.Run("weekday step with sunday as 7", func(t *testing.T) {
+		s, err := parser{}.parse("0 0 * * 1-7/2")
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 3, 5}, s.(*scheduleResult).dow.Values)
+	})
+
+	invalid := []string{"*/0", "5/", "/5", "10-5/2", "1-70/5", "*/a", "1/2/3", "*,5"}
+	for _, field := range invalid {
+		t.Run(fmt.Sprintf("invalid_%s", field), func(t *testing.T) {
+			_, err := parseField(field, 0, 59)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestCron_DayMatch 測試日與週欄位的 OR / AND 比對
+func TestCron_DayMatch(t *testing.T) {
+	date := func(m time.Month, d int) time.Time {
+		return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		dayAnd bool
+		spec   string
+		from   time.Time
+		want   time.Time
+	}{
+		{"or fires on monday", false, "0 0 1 * MON", date(1, 1), date(1, 5)},
+		{"or fires on first of month", false, "0 0 1 * MON", date(1, 26), date(2, 1)},
+		{"or ignored when weekday is star", false, "0 0 1 * *", date(1, 1), date(2, 1)},
+		{"or ignored when day is question mark", false, "0 0 ? * MON", date(1, 5), date(1, 12)},
+		{"stepped star day requires both", false, "0 0 */2 * MON", date(1, 1), date(1, 5)},
+		{"stepped star day skips even mondays", false, "0 0 */2 * MON", date(1, 5), date(1, 19)},
+		{"and requires both", true, "0 0 1 * MON", date(1, 1), date(6, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parser{dayAnd: tt.dayAnd}.parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.next(tt.from))
+		})
+	}
+
+	t.Run("config selects and", func(t *testing.T) {
+		c, err := New(Config{DayMatch: DayAnd})
+		require.NoError(t, err)
+		assert.True(t, c.parser.dayAnd)
+	})
+}
+
+// scanNext 逐分鐘掃描的舊實作，作為正確性與效能比較基準
+func scanNext(s *scheduleResult, t time.Time) time.Time {
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second)
+
+	for {
+		if s.matchTime(t) {
+			return t
+		}
+		t = t.Add(time.Minute)
+	}
+}
+
+// TestCron_NextMatchesScan 測試欄位跳躍結果與逐分鐘掃描一致
+func TestCron_NextMatchesScan(t *testing.T) {
+	specs := []string{
+		"* * * * *",
+		"*/7 * * * *",
+		"0 0 * * *",
+		"30 2 * * 1-5",
+		"0 9 1,15 * *",
+		"0 0 1 */3 *",
+		"0 0 L * *",
+		"0 12 15W * *",
+		"0 8 ? * FRI#3",
+		"0 0 1 * MON",
+		"@yearly",
+		"@weekly",
+	}
+	starts := []time.Time{
+		time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 28, 23, 59, 30, 0, time.UTC),
+		time.Date(2027, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(2028, 2, 28, 12, 0, 0, 0, time.UTC),
+	}
+
+	for _, spec := range specs {
+		s, err := parser{}.parse(spec)
+		require.NoError(t, err)
+		for _, start := range starts {
+			t.Run(fmt.Sprintf("%s_%s", spec, start.Format(time.RFC3339)), func(t *testing.T) {
+				schedule := s.(*scheduleResult)
+				from := start
+				for i := 0; i < 5; i++ {
+					want := scanNext(schedule, from)
+					got := schedule.next(from)
+					require.Equal(t, want, got)
+					from = got
+				}
+			})
+		}
+	}
+}
+
+// TestCron_NeverFires 測試不可能觸發的表達式
+func TestCron_NeverFires(t *testing.T) {
+	c := createTestCron(t)
+	defer cleanupCron(t, c)
+
+	for _, spec := range []string{"0 0 30 2 *", "0 0 31 4,6,9,11 *", "0 0 31W 2 *"} {
+		t.Run(spec, func(t *testing.T) {
+			done := make(chan error, 1)
+			go func() {
+				_, err := c.Add(spec, func() {})
+				done <- err
+			}()
+
+			select {
+			case err := <-done:
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "never fires")
+			case <-time.After(time.Second):
+				t.Fatal("Impossible spec should be rejected without hanging")
+			}
+		})
+	}
+
+	t.Run("next returns zero beyond horizon", func(t *testing.T) {
+		s := &scheduleResult{
+			second: scheduleField{Values: []int{0}},
+			minute: scheduleField{Values: []int{0}},
+			hour:   scheduleField{Values: []int{0}},
+			dom:    scheduleField{Values: []int{30}},
+			month:  scheduleField{Values: []int{2}},
+			dow:    scheduleField{All: true},
+		}
+		assert.True(t, s.next(time.Now()).IsZero())
+	})
+
+	t.Run("validates against the injected clock", func(t *testing.T) {
+		clock := &recordClock{Clock: NewFakeClock(time.Date(2090, 1, 1, 0, 0, 0, 0, time.UTC))}
+		c, err := New(Config{Location: time.UTC, Clock: clock})
+		require.NoError(t, err)
+
+		_, err = c.Add("0 0 29 2 *", func() {})
+		require.NoError(t, err)
+		assert.Greater(t, atomic.LoadInt32(&clock.calls), int32(0))
+	})
+}
+
+// * 記錄 Now 呼叫次數的 Clock
+type recordClock struct {
+	Clock
+	calls int32
+}
+
+func (r *recordClock) Now() time.Time {
+	atomic.AddInt32(&r.calls, 1)
+	return r.Clock.Now()
+}
+
+// BenchmarkSchedule_Next 欄位跳躍計算的效能測試
+func BenchmarkSchedule_Next(b *testing.B) {
+	for _, spec := range []string{"*/5 * * * *", "0 9 * * MON-FRI", "@yearly"} {
+		s, _ := parser{}.parse(spec)
+		schedule := s.(*scheduleResult)
+		from := time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC)
+
+		b.Run(spec, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				schedule.next(from)
+			}
+		})
+	}
+}
+
+// BenchmarkSchedule_NextScan 逐分鐘掃描舊實作的效能測試
+func BenchmarkSchedule_NextScan(b *testing.B) {
+	for _, spec := range []string{"*/5 * * * *", "0 9 * * MON-FRI", "@yearly"} {
+		s, _ := parser{}.parse(spec)
+		schedule := s.(*scheduleResult)
+		from := time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC)
+
+		b.Run(spec, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				scanNext(schedule, from)
+			}
+		})
+	}
+}
+
+// TestCron_TaskLocation 測試 CRON_TZ= 前綴與任務時區
+func TestCron_TaskLocation(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("CRON_TZ prefix", func(t *testing.T) {
+		s, err := parser{}.parse("CRON_TZ=Asia/Taipei 0 9 * * *")
+		require.NoError(t, err)
+		got := s.next(from)
+		assert.True(t, got.Equal(time.Date(2026, 1, 1, 9, 0, 0, 0, taipei)), got.String())
+		assert.Equal(t, taipei, got.Location())
+	})
+
+	t.Run("TZ prefix", func(t *testing.T) {
+		s, err := parser{}.parse("TZ=America/New_York 0 9 * * *")
+		require.NoError(t, err)
+		got := s.next(from)
+		assert.True(t, got.Equal(time.Date(2026, 1, 1, 9, 0, 0, 0, newYork)), got.String())
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		_, err := parser{}.parse("CRON_TZ=Mars/Olympus 0 9 * * *")
+		assert.Error(t, err)
+
+		_, err = parser{}.parse("CRON_TZ= 0 9 * * *")
+		assert.EqualError(t, err, "empty timezone in CRON_TZ=")
+		_, err = parser{}.parse("TZ= 0 9 * * *")
+		assert.EqualError(t, err, "empty timezone in TZ=")
+	})
+
+	t.Run("heap orders by absolute instant", func(t *testing.T) {
+		c := createTestCron(t)
+		defer cleanupCron(t, c)
+
+		nyID, err := c.Add("0 9 * * *", func() {}, newYork)
+		require.NoError(t, err)
+		tpID, err := c.Add("CRON_TZ=Asia/Taipei 0 9 * * *", func() {}, newYork)
+		require.NoError(t, err)
+
+		for _, e := range c.heap {
+			e.next = e.schedule.next(from)
+		}
+		heap.Init(&c.heap)
+
+		// * 前綴優先：台北 09:00 早於紐約 09:00
+		assert.Equal(t, tpID, c.heap[0].ID)
+		assert.True(t, c.heap[0].next.Equal(time.Date(2026, 1, 1, 9, 0, 0, 0, taipei)))
+		assert.Equal(t, nyID, c.heap[1].ID)
+		assert.True(t, c.heap[1].next.Equal(time.Date(2026, 1, 1, 9, 0, 0, 0, newYork)))
+	})
+}
+
+// TestCron_DSTTransitions 測試夏令時間轉換
+func TestCron_DSTTransitions(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	utc := func(m time.Month, d, h, min int) time.Time {
+		return time.Date(2026, m, d, h, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		policy   DSTPolicy
+		spec     string
+		from     time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "new york spring forward runs once after gap",
+			location: newYork,
+			policy:   DSTOnce,
+			spec:     "30 2 * * *",
+			from:     utc(3, 7, 17, 0),
+			// * 03-08 03:00 EDT、03-09 02:30 EDT
+			want: []time.Time{utc(3, 8, 7, 0), utc(3, 9, 6, 30)},
+		},
+		{
+			name:     "new york spring forward skipped with wall clock",
+			location: newYork,
+			policy:   DSTWallClock,
+			spec:     "30 2 * * *",
+			from:     utc(3, 7, 17, 0),
+			want:     []time.Time{utc(3, 9, 6, 30)},
+		},
+		{
+			name:     "new york gap minutes collapse into one run",
+			location: newYork,
+			policy:   DSTOnce,
+			spec:     "* * * * *",
+			from:     utc(3, 8, 6, 58),
+			// * 01:59 EST、03:00 EDT、03:01 EDT
+			want: []time.Time{utc(3, 8, 6, 59), utc(3, 8, 7, 0), utc(3, 8, 7, 1)},
+		},
+		{
+			name:     "new york fall back runs once",
+			location: newYork,
+			policy:   DSTOnce,
+			spec:     "30 1 * * *",
+			from:     utc(10, 31, 16, 0),
+			// * 11-01 01:30 EDT、11-02 01:30 EST
+			want: []time.Time{utc(11, 1, 5, 30), utc(11, 2, 6, 30)},
+		},
+		{
+			name:     "new york fall back runs twice with wall clock",
+			location: newYork,
+			policy:   DSTWallClock,
+			spec:     "30 1 * * *",
+			from:     utc(10, 31, 16, 0),
+			want:     []time.Time{utc(11, 1, 5, 30), utc(11, 1, 6, 30), utc(11, 2, 6, 30)},
+		},
+		{
+			name:     "new york repeated hour kept for frequent jobs",
+			location: newYork,
+			policy:   DSTOnce,
+			spec:     "*/15 * * * *",
+			from:     utc(11, 1, 5, 40),
+			// * 01:45 EDT 之後依實際時間：01:00 EST 至 02:00 EST
+			want: []time.Time{
+				utc(11, 1, 5, 45), utc(11, 1, 6, 0), utc(11, 1, 6, 15),
+				utc(11, 1, 6, 30), utc(11, 1, 6, 45), utc(11, 1, 7, 0),
+			},
+		},
+		{
+			name:     "new york repeated hour kept for hourly jobs",
+			location: newYork,
+			policy:   DSTOnce,
+			spec:     "0 * * * *",
+			from:     utc(11, 1, 4, 30),
+			// * 01:00 EDT、01:00 EST、02:00 EST
+			want: []time.Time{utc(11, 1, 5, 0), utc(11, 1, 6, 0), utc(11, 1, 7, 0)},
+		},
+		{
+			name:     "new york repeated hour replayed with wall clock",
+			location: newYork,
+			policy:   DSTWallClock,
+			spec:     "*/30 * * * *",
+			from:     utc(11, 1, 5, 10),
+			// * 01:30 EDT、01:00 EST、01:30 EST、02:00 EST
+			want: []time.Time{utc(11, 1, 5, 30), utc(11, 1, 6, 0), utc(11, 1, 6, 30), utc(11, 1, 7, 0)},
+		},
+		{
+			name:     "london spring forward runs once after gap",
+			location: london,
+			policy:   DSTOnce,
+			spec:     "30 1 * * *",
+			from:     utc(3, 28, 12, 0),
+			// * 03-29 02:00 BST、03-30 01:30 BST
+			want: []time.Time{utc(3, 29, 1, 0), utc(3, 30, 0, 30)},
+		},
+		{
+			name:     "london spring forward skipped with wall clock",
+			location: london,
+			policy:   DSTWallClock,
+			spec:     "30 1 * * *",
+			from:     utc(3, 28, 12, 0),
+			want:     []time.Time{utc(3, 30, 0, 30)},
+		},
+		{
+			name:     "london fall back runs once",
+			location: london,
+			policy:   DSTOnce,
+			spec:     "30 1 * * *",
+			from:     utc(10, 24, 12, 0),
+			// * 10-25 01:30 BST、10-26 01:30 GMT
+			want: []time.Time{utc(10, 25, 0, 30), utc(10, 26, 1, 30)},
+		},
+		{
+			name:     "london fall back runs twice with wall clock",
+			location: london,
+			policy:   DSTWallClock,
+			spec:     "30 1 * * *",
+			from:     utc(10, 24, 12, 0),
+			want:     []time.Time{utc(10, 25, 0, 30), utc(10, 25, 1, 30), utc(10, 26, 1, 30)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parser{dst: tt.policy}.parse(tt.spec)
+			require.NoError(t, err)
+
+			from := tt.from.In(tt.location)
+			for _, want := range tt.want {
+				got := s.next(from)
+				require.True(t, want.Equal(got), "want %s, got %s", want.In(tt.location), got)
+				from = got
+			}
+		})
+	}
+
+	t.Run("per task policy overrides config", func(t *testing.T) {
+		c, err := New(Config{Location: newYork})
+		require.NoError(t, err)
+		defer cleanupCron(t, c)
+
+		_, err = c.Add("30 2 * * *", func() {}, DSTWallClock)
+		require.NoError(t, err)
+
+		got := c.heap[0].schedule.next(utc(3, 7, 17, 0).In(newYork))
+		assert.True(t, utc(3, 9, 6, 30).Equal(got), got.String())
+	})
+}
+
+// TestFakeClock 測試可手動推進的時間來源
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	timer := clock.NewTimer(time.Minute)
+	stopped := clock.NewTimer(time.Minute)
+	fired := make(chan struct{}, 1)
+	clock.AfterFunc(30*time.Second, func() {
+		fired <- struct{}{}
+	})
+
+	clock.BlockUntil(3)
+	assert.True(t, stopped.Stop())
+	assert.False(t, stopped.Stop())
+
+	clock.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(30*time.Second), clock.Now())
+	select {
+	case <-fired:
+	default:
+		t.Fatal("AfterFunc should fire when its time is reached")
+	}
+	select {
+	case <-timer.C():
+		t.Fatal("Timer should not fire early")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case now := <-timer.C():
+		assert.Equal(t, start.Add(time.Minute), now)
+	default:
+		t.Fatal("Timer should fire after Advance")
+	}
+	select {
+	case <-stopped.C():
+		t.Fatal("Stopped timer should not fire")
+	default:
+	}
+
+	assert.False(t, timer.Reset(time.Second))
+	clock.Set(start)
+	assert.Equal(t, start.Add(time.Minute), clock.Now(), "Set should not move time backwards")
+	clock.Set(start.Add(time.Hour))
+	select {
+	case <-timer.C():
+	default:
+		t.Fatal("Reset timer should fire after Set")
+	}
+}
+
+// createFakeClockCron 以 FakeClock 建立並啟動排程
+func createFakeClockCron(t *testing.T) (*cron, *FakeClock) {
+	t.Helper()
+
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	c, err := New(Config{
+		Location: time.UTC,
+		Clock:    clock,
+	})
+	require.NoError(t, err)
+
+	return c, clock
+}
+
+// TestCron_FakeClockExecution 測試以 FakeClock 決定性觸發排程
+func TestCron_FakeClockExecution(t *testing.T) {
+	c, clock := createFakeClockCron(t)
+	defer cleanupCron(t, c)
+
+	executed := make(chan time.Time, 10)
+	_, err := c.Add("@every 1m", func() {
+		executed <- clock.Now()
+	})
+	require.NoError(t, err)
+	c.Start()
+
+	for i := 1; i <= 3; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+
+		select {
+		case at := <-executed:
+			assert.Equal(t, time.Date(2026, 1, 1, 0, i, 0, 0, time.UTC), at)
+		case <-time.After(time.Second):
+			t.Fatalf("Run %d should execute after advancing the clock", i)
+		}
+	}
+}
+
+// TestCron_FakeClockTimeout 測試以 FakeClock 觸發任務逾時
+func TestCron_FakeClockTimeout(t *testing.T) {
+	c, clock := createFakeClockCron(t)
+	defer cleanupCron(t, c)
+
+	timeoutTriggered := make(chan struct{}, 1)
+	cancelled := make(chan error, 1)
+	_, err := c.Add("@every 1m", func(ctx context.Context) error {
+		<-ctx.Done()
+		cancelled <- context.Cause(ctx)
+		return nil
+	}, 10*time.Second, func() {
+		timeoutTriggered <- struct{}{}
+	})
+	require.NoError(t, err)
+	c.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	// * 下一次排程與逾時各一個 timer
+	clock.BlockUntil(2)
+	clock.Advance(10 * time.Second)
+
+	select {
+	case <-timeoutTriggered:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout callback should be triggered by the fake clock")
+	}
+	select {
+	case err := <-cancelled:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("Action context should be cancelled on timeout")
+	}
+}
+
+// TestCron_FakeClockDependencyTimeout 測試以 FakeClock 觸發依賴等待逾時
+func TestCron_FakeClockDependencyTimeout(t *testing.T) {
+	c, clock := createFakeClockCron(t)
+	defer cleanupCron(t, c)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	parentID, err := c.Add("@every 1m", func() error {
+		<-release
+		return nil
+	})
+	require.NoError(t, err)
+	childID, err := c.Add("@every 1m", func() error {
+		return nil
+	}, []Wait{{ID: parentID}})
+	require.NoError(t, err)
+	c.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	// * 下一次排程與依賴等待各一個 timer
+	clock.BlockUntil(2)
+	clock.Advance(time.Minute)
+
+	child := c.depend.manager.list[childID]
+	require.Eventually(t, func() bool {
+		child.mutex.RLock()
+		defer child.mutex.RUnlock()
+		return child.result != nil && child.result.status == TaskFailed
+	}, time.Second, time.Millisecond)
+
+	child.mutex.RLock()
+	defer child.mutex.RUnlock()
+	assert.Contains(t, child.result.error.Error(), "timeout waiting for dependencies")
+}
+
+// TestCron_OverlapPolicy 測試前一次執行尚未結束時的處理方式
+func TestCron_OverlapPolicy(t *testing.T) {
+	type overlapTask struct {
+		c       *cron
+		clock   *FakeClock
+		entry   *task
+		starts  chan struct{}
+		ends    chan error
+		release chan struct{}
+	}
+
+	setup := func(t *testing.T, policy OverlapPolicy) *overlapTask {
+		c, clock := createFakeClockCron(t)
+		o := &overlapTask{
+			c:       c,
+			clock:   clock,
+			starts:  make(chan struct{}, 10),
+			ends:    make(chan error, 10),
+			release: make(chan struct{}, 10),
+		}
+		id, err := c.Add("@every 1m", func(ctx context.Context) error {
+			o.starts <- struct{}{}
+			var err error
+			select {
+			case <-o.release:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+			o.ends <- err
+			return err
+		}, policy)
+		require.NoError(t, err)
+		o.entry = c.depend.manager.list[id]
+		c.Start()
+		return o
+	}
+
+	// * 推進一分鐘並等待排程迴圈處理完畢
+	tick := func(o *overlapTask) {
+		o.clock.BlockUntil(1)
+		o.clock.Advance(time.Minute)
+		o.clock.BlockUntil(1)
+	}
+
+	expectStart := func(t *testing.T, o *overlapTask) {
+		t.Helper()
+		select {
+		case <-o.starts:
+		case <-time.After(time.Second):
+			t.Fatal("task did not start")
+		}
+	}
+
+	expectNoStart := func(t *testing.T, o *overlapTask) {
+		t.Helper()
+		select {
+		case <-o.starts:
+			t.Fatal("task started unexpectedly")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	counters := func(o *overlapTask) (int, int) {
+		info, _ := o.c.Get(o.entry.ID)
+		return info.Skipped, info.Queued
+	}
+
+	t.Run("allow", func(t *testing.T) {
+		o := setup(t, OverlapAllow)
+		defer cleanupCron(t, o.c)
+
+		tick(o)
+		expectStart(t, o)
+		tick(o)
+		expectStart(t, o)
+
+		o.release <- struct{}{}
+		o.release <- struct{}{}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		o := setup(t, OverlapSkip)
+		defer cleanupCron(t, o.c)
+
+		tick(o)
+		expectStart(t, o)
+		tick(o)
+		expectNoStart(t, o)
+
+		skipped, queued := counters(o)
+		assert.Equal(t, 1, skipped)
+		assert.Equal(t, 0, queued)
+
+		// * 前一次結束後恢復正常排程
+		o.release <- struct{}{}
+		<-o.ends
+		require.Eventually(t, func() bool {
+			o.entry.mutex.RLock()
+			defer o.entry.mutex.RUnlock()
+			return o.entry.running == 0
+		}, time.Second, time.Millisecond)
+		tick(o)
+		expectStart(t, o)
+		o.release <- struct{}{}
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		o := setup(t, OverlapQueue)
+		defer cleanupCron(t, o.c)
+
+		tick(o)
+		expectStart(t, o)
+		tick(o)
+		tick(o)
+		expectNoStart(t, o)
+
+		// * 只保留一次待執行
+		skipped, queued := counters(o)
+		assert.Equal(t, 1, skipped)
+		assert.Equal(t, 1, queued)
+
+		o.release <- struct{}{}
+		expectStart(t, o)
+		o.release <- struct{}{}
+		<-o.ends
+		<-o.ends
+		expectNoStart(t, o)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		o := setup(t, OverlapReplace)
+		defer cleanupCron(t, o.c)
+
+		tick(o)
+		expectStart(t, o)
+		tick(o)
+
+		select {
+		case err := <-o.ends:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("previous run was not cancelled")
+		}
+		expectStart(t, o)
+		o.release <- struct{}{}
+	})
+}
+
+// TestCron_Misfire 測試延後啟動時錯過排程的處理方式
+func TestCron_Misfire(t *testing.T) {
+	tests := []struct {
+		name    string
+		misfire Misfire
+		want    int
+		missed  int
+	}{
+		{"disabled", Misfire{}, 0, 0},
+		{"fire once", Misfire{Policy: MisfireFireOnce, Threshold: 10 * time.Second}, 1, 1},
+		{"fire all", Misfire{Policy: MisfireFireAll, Threshold: 10 * time.Second}, 5, 1},
+		{"fire all limit", Misfire{Policy: MisfireFireAll, Threshold: 10 * time.Second, Limit: 3}, 3, 1},
+		{"skip", Misfire{Policy: MisfireSkip, Threshold: 10 * time.Second}, 0, 1},
+		{"within threshold", Misfire{Policy: MisfireSkip, Threshold: 10 * time.Minute}, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, clock := createFakeClockCron(t)
+			defer cleanupCron(t, c)
+
+			var count int32
+			id, err := c.Add("@every 1m", func() {
+				atomic.AddInt32(&count, 1)
+			}, tt.misfire)
+			require.NoError(t, err)
+
+			// * 啟動前經過五個排程週期
+			clock.Advance(5*time.Minute + 30*time.Second)
+			c.Start()
+			clock.BlockUntil(1)
+			time.Sleep(50 * time.Millisecond)
+
+			assert.Equal(t, int32(tt.want), atomic.LoadInt32(&count))
+
+			info, ok := c.Get(id)
+			require.True(t, ok)
+			assert.Equal(t, tt.missed, info.Missed)
+
+			// * 之後恢復正常排程
+			clock.Advance(time.Minute)
+			clock.BlockUntil(1)
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&count) == int32(tt.want+1)
+			}, time.Second, time.Millisecond)
+		})
+	}
+}
+
+// TestCron_MisfireConfig 測試 Config 預設值與逐任務覆寫
+func TestCron_MisfireConfig(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	c, err := New(Config{
+		Location: time.UTC,
+		Clock:    clock,
+		Misfire:  Misfire{Policy: MisfireSkip, Threshold: time.Second},
+	})
+	require.NoError(t, err)
+	defer cleanupCron(t, c)
+
+	var inherited, overridden int32
+	_, err = c.Add("@every 1m", func() {
+		atomic.AddInt32(&inherited, 1)
+	})
+	require.NoError(t, err)
+	_, err = c.Add("@every 1m", func() {
+		atomic.AddInt32(&overridden, 1)
+	}, Misfire{Policy: MisfireFireOnce, Threshold: time.Second})
+	require.NoError(t, err)
+
+	clock.Advance(3 * time.Minute)
+	c.Start()
+	clock.BlockUntil(1)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&overridden) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&inherited))
+}
+
+// * 等待排程設定指定時間的 timer
+func waitTimerAt(t *testing.T, clock *FakeClock, at time.Time) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+		for _, timer := range clock.timers {
+			if timer.when.Equal(at) {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+}
+
+// TestRetry_Backoff 測試重試等待時間的計算
+func TestRetry_Backoff(t *testing.T) {
+	errRetry := errors.New("retry")
+	errFatal := errors.New("fatal")
+
+	tests := []struct {
+		name    string
+		retry   Retry
+		attempt int
+		err     error
+		want    time.Duration
+		ok      bool
+	}{
+		{"disabled", Retry{}, 1, errRetry, 0, false},
+		{"default delay", Retry{MaxAttempts: 3}, 1, errRetry, time.Second, true},
+		{"exponential", Retry{MaxAttempts: 5, Delay: time.Second}, 3, errRetry, 4 * time.Second, true},
+		{"multiplier", Retry{MaxAttempts: 5, Delay: time.Second, Multiplier: 3}, 3, errRetry, 9 * time.Second, true},
+		{"max delay", Retry{MaxAttempts: 10, Delay: time.Second, MaxDelay: 5 * time.Second}, 8, errRetry, 5 * time.Second, true},
+		{"exhausted", Retry{MaxAttempts: 3}, 3, errRetry, 0, false},
+		{"not retryable", Retry{MaxAttempts: 3, Retryable: func(err error) bool {
+			return !errors.Is(err, errFatal)
+		}}, 1, errFatal, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.retry.backoff(tt.attempt, tt.err)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("jitter", func(t *testing.T) {
+		r := Retry{MaxAttempts: 3, Delay: 10 * time.Second, Jitter: 0.5}
+		for i := 0; i < 100; i++ {
+			got, ok := r.backoff(1, errRetry)
+			require.True(t, ok)
+			assert.GreaterOrEqual(t, got, 5*time.Second)
+			assert.LessOrEqual(t, got, 15*time.Second)
+		}
+	})
+}
+
+// TestCron_Retry 測試失敗任務透過 heap 排入重試
+func TestCron_Retry(t *testing.T) {
+	c, clock := createFakeClockCron(t)
+	defer cleanupCron(t, c)
+
+	attempts := make(chan int, 10)
+	var calls int32
+	id, err := c.Add("@every 1h", func() error {
+		n := atomic.AddInt32(&calls, 1)
+		attempts <- int(n)
+		if n < 3 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	}, Retry{MaxAttempts: 3, Delay: time.Minute})
+	require.NoError(t, err)
+	entry := c.depend.manager.list[id]
+	c.Start()
+
+	start := clock.Now()
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	assert.Equal(t, 1, <-attempts)
+
+	// * 第二次於一分鐘後、第三次於再兩分鐘後執行
+	waitTimerAt(t, clock, start.Add(time.Hour+time.Minute))
+	entry.mutex.RLock()
+	assert.Equal(t, TaskRetrying, entry.state)
+	assert.Equal(t, 1, entry.result.attempt)
+	entry.mutex.RUnlock()
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, 2, <-attempts)
+
+	waitTimerAt(t, clock, start.Add(time.Hour+3*time.Minute))
+	clock.Advance(2 * time.Minute)
+	assert.Equal(t, 3, <-attempts)
+
+	require.Eventually(t, func() bool {
+		entry.mutex.RLock()
+		defer entry.mutex.RUnlock()
+		return entry.state == TaskCompleted
+	}, time.Second, time.Millisecond)
+
+	entry.mutex.RLock()
+	assert.Equal(t, 3, entry.result.attempt)
+	entry.mutex.RUnlock()
+
+	// * 下一次排程重新計算次數
+	waitTimerAt(t, clock, start.Add(2*time.Hour))
+}
+
+// TestCron_RetryDependents 測試重試用盡前依賴任務持續等待
+func TestCron_RetryDependents(t *testing.T) {
+	c, clock := createFakeClockCron(t)
+	defer cleanupCron(t, c)
+
+	var calls int32
+	parentID, err := c.Add("@every 1h", func() error {
+		atomic.AddInt32(&calls, 1)
+		return errors.New("always fails")
+	}, Retry{MaxAttempts: 2, Delay: time.Minute})
+	require.NoError(t, err)
+	childID, err := c.Add("@every 1h", func() error {
+		return nil
+	}, []Wait{{ID: parentID, Delay: 10 * time.Minute}})
+	require.NoError(t, err)
+	parent := c.depend.manager.list[parentID]
+	child := c.depend.manager.list[childID]
+	c.Start()
+
+	start := clock.Now()
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	waitTimerAt(t, clock, start.Add(time.Hour+time.Minute))
+	time.Sleep(20 * time.Millisecond)
+	child.mutex.RLock()
+	assert.Nil(t, child.result)
+	child.mutex.RUnlock()
+
+	clock.Advance(time.Minute)
+	require.Eventually(t, func() bool {
+		child.mutex.RLock()
+		defer child.mutex.RUnlock()
+		return child.result != nil && child.result.status == TaskFailed
+	}, time.Second, time.Millisecond)
+
+	parent.mutex.RLock()
+	assert.Equal(t, TaskFailed, parent.state)
+	assert.Equal(t, 2, parent.result.attempt)
+	parent.mutex.RUnlock()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+// TestCron_Options 測試 Option 套用至任務
+func TestCron_Options(t *testing.T) {
+	c := createTestCron(t)
+	defer cleanupCron(t, c)
+
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	onTimeout := func() {}
+	retry := Retry{MaxAttempts: 3}
+	for i := 0; i < 2; i++ {
+		_, err := c.Add("@every 1m", func() error { return nil })
+		require.NoError(t, err)
+	}
+
+	id, err := c.Add("0 9 * * *", func() error { return nil },
+		WithName("report"),
+		WithDescription("daily report"),
+		WithTimeout(5*time.Second),
+		WithOnTimeout(onTimeout),
+		WithDependencies(Wait{ID: 1, Delay: 10 * time.Second}, Wait{ID: 2, Delay: time.Minute}),
+		WithLocation(taipei),
+		WithDST(DSTWallClock),
+		WithOverlap(OverlapQueue),
+		WithMisfire(Misfire{Policy: MisfireSkip}),
+		WithRetry(retry),
+	)
+	require.NoError(t, err)
+
+	entry := c.depend.manager.list[id]
+	assert.Equal(t, "report", entry.name)
+	assert.Equal(t, "daily report", entry.description)
+	assert.Equal(t, 5*time.Second, entry.delay)
+	assert.NotNil(t, entry.onDelay)
+	assert.Equal(t, []Wait{{ID: 1, Delay: 10 * time.Second}, {ID: 2, Delay: time.Minute}}, entry.after)
+	assert.Equal(t, time.Minute, entry.wait, "dependency timeout should use the longest Wait.Delay")
+	assert.Equal(t, OverlapQueue, entry.overlap)
+	assert.Equal(t, MisfireSkip, entry.misfire.Policy)
+	assert.Equal(t, 3, entry.retry.MaxAttempts)
+
+	result := entry.schedule.(*scheduleResult)
+	assert.Equal(t, taipei, result.location)
+	assert.Equal(t, DSTWallClock, result.dst)
+
+	// * 表達式的 CRON_TZ= 前綴優先
+	id, err = c.Add("CRON_TZ=UTC 0 9 * * *", func() {}, WithLocation(taipei))
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, c.depend.manager.list[id].schedule.(*scheduleResult).location)
+}
+
+// TestCron_TaskInfo 測試任務快照與 Get
+func TestCron_TaskInfo(t *testing.T) {
+	c, clock := createFakeClockCron(t)
+	defer cleanupCron(t, c)
+
+	start := clock.Now()
+	var calls int32
+	parentID, err := c.Add("@every 1m", func() error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return errors.New("first run fails")
+		}
+		return nil
+	}, WithName("parent"), WithDescription("flaky parent"))
+	require.NoError(t, err)
+	childID, err := c.Add("*/5 * * * *", func() error {
+		return nil
+	}, WithDependencies(Wait{ID: parentID, State: Skip}))
+	require.NoError(t, err)
+
+	info, ok := c.Get(parentID)
+	require.True(t, ok)
+	assert.Equal(t, parentID, info.ID)
+	assert.Equal(t, "parent", info.Name)
+	assert.Equal(t, "flaky parent", info.Description)
+	assert.Equal(t, "@every 1m", info.Spec)
+	assert.Equal(t, TaskPending, info.State)
+	assert.Nil(t, info.LastResult)
+
+	child, ok := c.Get(childID)
+	require.True(t, ok)
+	assert.Equal(t, []Wait{{ID: parentID, State: Skip}}, child.Dependencies)
+
+	// * 快照不影響任務本身
+	child.Dependencies[0].ID = 99
+	child, _ = c.Get(childID)
+	assert.Equal(t, parentID, child.Dependencies[0].ID)
+
+	c.Start()
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		n := i + 1
+		require.Eventually(t, func() bool {
+			info, _ := c.Get(parentID)
+			return info.Runs == n
+		}, time.Second, time.Millisecond)
+	}
+
+	info, _ = c.Get(parentID)
+	assert.Equal(t, TaskCompleted, info.State)
+	assert.Equal(t, 2, info.Runs)
+	assert.Equal(t, 1, info.Failures)
+	require.NotNil(t, info.LastResult)
+	assert.Equal(t, TaskCompleted, info.LastResult.Status)
+	assert.NoError(t, info.LastResult.Error)
+	assert.Equal(t, start.Add(2*time.Minute), info.Prev)
+	assert.Equal(t, start.Add(3*time.Minute), info.Next)
+
+	tasks := c.List()
+	require.Len(t, tasks, 2)
+	assert.Equal(t, parentID, tasks[0].ID)
+	assert.Equal(t, childID, tasks[1].ID)
+
+	c.Remove(parentID)
+	_, ok = c.Get(parentID)
+	assert.False(t, ok)
+	_, ok = c.Get(12345)
+	assert.False(t, ok)
+}
+
+// TestCron_PauseResume 測試暫停與恢復任務
+func TestCron_PauseResume(t *testing.T) {
+	setup := func(t *testing.T) (*cron, *FakeClock, int64, *int32) {
+		c, clock := createFakeClockCron(t)
+		count := new(int32)
+		id, err := c.Add("@every 1m", func() {
+			atomic.AddInt32(count, 1)
+		}, WithName("paused"))
+		require.NoError(t, err)
+		c.Start()
+		clock.BlockUntil(1)
+		return c, clock, id, count
+	}
+
+	// * 推進時間並等待排程迴圈處理完畢
+	tick := func(clock *FakeClock, d time.Duration) {
+		clock.Advance(d)
+		clock.BlockUntil(1)
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Run("resume without catch-up", func(t *testing.T) {
+		c, clock, id, count := setup(t)
+		defer cleanupCron(t, c)
+
+		require.NoError(t, c.Pause(id))
+		info, _ := c.Get(id)
+		assert.True(t, info.Paused)
+
+		tick(clock, 3*time.Minute)
+		assert.Equal(t, int32(0), atomic.LoadInt32(count))
+
+		// * 自恢復時間重新計算下一次執行
+		clock.Advance(30 * time.Second)
+		require.NoError(t, c.Resume(id, false))
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int32(0), atomic.LoadInt32(count))
+
+		info, _ = c.Get(id)
+		assert.False(t, info.Paused)
+		assert.Equal(t, clock.Now().Add(time.Minute), info.Next)
+
+		tick(clock, time.Minute)
+		assert.Equal(t, int32(1), atomic.LoadInt32(count))
+	})
+
+	t.Run("resume with catch-up", func(t *testing.T) {
+		c, clock, id, count := setup(t)
+		defer cleanupCron(t, c)
+
+		require.NoError(t, c.Pause(id))
+		tick(clock, 3*time.Minute)
+		require.NoError(t, c.Resume(id, true))
+
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(count) == 1
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("catch-up without missed run", func(t *testing.T) {
+		c, _, id, count := setup(t)
+		defer cleanupCron(t, c)
+
+		require.NoError(t, c.Pause(id))
+		require.NoError(t, c.Resume(id, true))
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int32(0), atomic.LoadInt32(count))
+	})
+
+	t.Run("pause all", func(t *testing.T) {
+		c, clock, id, count := setup(t)
+		defer cleanupCron(t, c)
+
+		var other int32
+		otherID, err := c.Add("@every 1m", func() {
+			atomic.AddInt32(&other, 1)
+		})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			info, _ := c.Get(otherID)
+			return !info.Next.IsZero()
+		}, time.Second, time.Millisecond)
+
+		c.PauseAll()
+		tick(clock, 2*time.Minute)
+		assert.Equal(t, int32(0), atomic.LoadInt32(count))
+		assert.Equal(t, int32(0), atomic.LoadInt32(&other))
+
+		c.ResumeAll(true)
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(count) == 1 && atomic.LoadInt32(&other) == 1
+		}, time.Second, time.Millisecond)
+
+		for _, taskID := range []int64{id, otherID} {
+			info, _ := c.Get(taskID)
+			assert.False(t, info.Paused)
+		}
+	})
+
+	t.Run("pause all covers new tasks", func(t *testing.T) {
+		c, clock, _, _ := setup(t)
+		defer cleanupCron(t, c)
+
+		c.PauseAll()
+		var added int32
+		addedID, err := c.Add("@every 1m", func() {
+			atomic.AddInt32(&added, 1)
+		})
+		require.NoError(t, err)
+
+		info, _ := c.Get(addedID)
+		assert.True(t, info.Paused)
+		tick(clock, 2*time.Minute)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&added))
+
+		c.ResumeAll(false)
+		info, _ = c.Get(addedID)
+		assert.False(t, info.Paused)
+		tick(clock, time.Minute)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&added))
+
+		// * ResumeAll 之後新增的任務不再暫停
+		laterID, err := c.Add("@every 1m", func() {})
+		require.NoError(t, err)
+		info, _ = c.Get(laterID)
+		assert.False(t, info.Paused)
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		c, _ := createFakeClockCron(t)
+		assert.EqualError(t, c.Pause(42), "task not found: 42")
+		assert.EqualError(t, c.Resume(42, false), "task not found: 42")
+	})
+}
+
+// TestCron_RunNow 測試手動立即執行任務
+func TestCron_RunNow(t *testing.T) {
+	receive := func(t *testing.T, done <-chan TaskResult) TaskResult {
+		t.Helper()
+		select {
+		case result, ok := <-done:
+			require.True(t, ok, "result channel closed without a result")
+			return result
+		case <-time.After(time.Second):
+			t.Fatal("no result from RunNow")
+			return TaskResult{}
+		}
+	}
+
+	t.Run("keeps schedule", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		id, err := c.Add("@every 1h", func() error {
+			return errors.New("nightly failed")
+		}, WithName("nightly"))
+		require.NoError(t, err)
+		c.Start()
+		clock.BlockUntil(1)
+
+		done, err := c.RunNow(id)
+		require.NoError(t, err)
+		result := receive(t, done)
+		assert.Equal(t, TaskFailed, result.Status)
+		assert.EqualError(t, result.Error, "nightly failed")
+		assert.Equal(t, 1, result.Attempt)
+
+		info, _ := c.Get(id)
+		assert.Equal(t, 1, info.Runs)
+		assert.Equal(t, clock.Now().Add(time.Hour), info.Next)
+		assert.True(t, info.Prev.IsZero())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		id, err := c.Add("@every 1h", func() {
+			panic("boom")
+		})
+		require.NoError(t, err)
+		c.Start()
+		clock.BlockUntil(1)
+
+		done, err := c.RunNow(id)
+		require.NoError(t, err)
+		result := receive(t, done)
+		assert.Equal(t, TaskFailed, result.Status)
+		assert.Contains(t, result.Error.Error(), "task panic: boom")
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		parentID, err := c.Add("@every 1h", func() error {
+			return nil
+		})
+		require.NoError(t, err)
+		childID, err := c.Add("@every 1h", func() error {
+			return nil
+		}, WithDependencies(Wait{ID: parentID, Delay: time.Minute}))
+		require.NoError(t, err)
+		c.Start()
+		clock.BlockUntil(1)
+
+		// * 前置任務完成前持續等待
+		child, err := c.RunNow(childID)
+		require.NoError(t, err)
+		select {
+		case <-child:
+			t.Fatal("dependent ran before its prerequisite")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		parent, err := c.RunNow(parentID)
+		require.NoError(t, err)
+		assert.Equal(t, TaskCompleted, receive(t, parent).Status)
+		assert.Equal(t, TaskCompleted, receive(t, child).Status)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		release := make(chan struct{})
+		id, err := c.Add("@every 1h", func() {
+			<-release
+		}, WithOverlap(OverlapSkip))
+		require.NoError(t, err)
+
+		_, err = c.RunNow(id)
+		assert.EqualError(t, err, "scheduler is not running")
+
+		c.Start()
+		clock.BlockUntil(1)
+
+		_, err = c.RunNow(42)
+		assert.EqualError(t, err, "task not found: 42")
+
+		done, err := c.RunNow(id)
+		require.NoError(t, err)
+		_, err = c.RunNow(id)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "task skipped")
+
+		close(release)
+		assert.Equal(t, TaskCompleted, receive(t, done).Status)
+	})
+}
+
+// TestCron_Update 測試就地更新任務排程與設定
+func TestCron_Update(t *testing.T) {
+	t.Run("running", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		var parentRuns, childRuns int32
+		parentID, err := c.Add("@every 1h", func() error {
+			atomic.AddInt32(&parentRuns, 1)
+			return nil
+		}, WithName("parent"), WithTimeout(time.Minute))
+		require.NoError(t, err)
+		childID, err := c.Add("@every 1h", func() error {
+			atomic.AddInt32(&childRuns, 1)
+			return nil
+		}, WithDependencies(Wait{ID: parentID}))
+		require.NoError(t, err)
+		c.Start()
+		clock.BlockUntil(1)
+
+		start := clock.Now()
+		done, err := c.RunNow(parentID)
+		require.NoError(t, err)
+		<-done
+
+		require.NoError(t, c.Update(parentID, "@every 5m", WithDescription("faster")))
+
+		info, ok := c.Get(parentID)
+		require.True(t, ok)
+		assert.Equal(t, "@every 5m", info.Spec)
+		assert.Equal(t, "parent", info.Name)
+		assert.Equal(t, "faster", info.Description)
+		assert.Equal(t, 1, info.Runs)
+
+		// * 下一次執行由排程迴圈重新計算
+		assert.Eventually(t, func() bool {
+			info, _ := c.Get(parentID)
+			return info.Next.Equal(start.Add(5 * time.Minute))
+		}, time.Second, time.Millisecond)
+
+		// * 依賴關係保持不變
+		child, _ := c.Get(childID)
+		assert.Equal(t, []Wait{{ID: parentID}}, child.Dependencies)
+
+		clock.BlockUntil(1)
+		clock.Advance(5 * time.Minute)
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&parentRuns) == 2
+		}, time.Second, time.Millisecond)
+
+		done, err = c.RunNow(childID)
+		require.NoError(t, err)
+		assert.Equal(t, TaskCompleted, (<-done).Status)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&childRuns))
+	})
+
+	t.Run("before start", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		var runs int32
+		id, err := c.Add("@every 1h", func() {
+			atomic.AddInt32(&runs, 1)
+		})
+		require.NoError(t, err)
+		require.NoError(t, c.Update(id, "@every 1m"))
+
+		c.Start()
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&runs) == 1
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		c, _ := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		parentID, err := c.Add("@every 1h", func() error { return nil })
+		require.NoError(t, err)
+		id, err := c.Add("@every 1h", func() error { return nil })
+		require.NoError(t, err)
+
+		require.NoError(t, c.Update(id, "@every 1h", WithDependencies(Wait{ID: parentID, Delay: time.Minute})))
+		info, _ := c.Get(id)
+		assert.Equal(t, []Wait{{ID: parentID, Delay: time.Minute}}, info.Dependencies)
+		assert.Equal(t, time.Minute, c.tasks[id].wait)
+	})
+
+	t.Run("keeps location", func(t *testing.T) {
+		c, _ := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		taipei, err := time.LoadLocation("Asia/Taipei")
+		require.NoError(t, err)
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+
+		id, err := c.Add("0 9 * * *", func() {}, WithLocation(taipei), WithDST(DSTWallClock))
+		require.NoError(t, err)
+
+		require.NoError(t, c.Update(id, "0 10 * * *"))
+		result := c.tasks[id].schedule.(*scheduleResult)
+		assert.Equal(t, taipei, result.location)
+		assert.Equal(t, DSTWallClock, result.dst)
+
+		// * 表達式的 CRON_TZ= 前綴優先
+		require.NoError(t, c.Update(id, "CRON_TZ=Asia/Tokyo 0 10 * * *"))
+		result = c.tasks[id].schedule.(*scheduleResult)
+		assert.Equal(t, tokyo, result.location)
+		assert.Equal(t, DSTWallClock, result.dst)
+
+		// * Option 覆寫沿用的時區
+		newYork, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		require.NoError(t, c.Update(id, "0 9 * * *", WithLocation(taipei)))
+		require.NoError(t, c.Update(id, "0 9 * * *", WithLocation(newYork)))
+		result = c.tasks[id].schedule.(*scheduleResult)
+		assert.Equal(t, newYork, result.location)
+
+		// * 前綴的時區不沿用至未帶前綴的表達式
+		require.NoError(t, c.Update(id, "CRON_TZ=Asia/Tokyo 0 10 * * *"))
+		require.NoError(t, c.Update(id, "0 10 * * *", WithDST(DSTOnce)))
+		result = c.tasks[id].schedule.(*scheduleResult)
+		assert.Nil(t, result.location)
+		assert.Equal(t, DSTOnce, result.dst)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		c, _ := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		id, err := c.Add("@every 1h", func() {}, WithDescription("original"))
+		require.NoError(t, err)
+
+		err = c.Update(42, "@every 1h")
+		assert.EqualError(t, err, "task not found: 42")
+
+		err = c.Update(id, "invalid spec")
+		assert.ErrorContains(t, err, "failed to parse")
+
+		err = c.Update(id, "@every 5m", WithDescription("changed"), WithTimeout(-time.Second))
+		assert.ErrorContains(t, err, "timeout must be positive")
+
+		err = c.Update(id, "@every 5m", WithDependencies(Wait{ID: 1}))
+		assert.ErrorContains(t, err, "need return value to get dependence support")
+
+		// * 失敗時不留下部分變更
+		info, _ := c.Get(id)
+		assert.Equal(t, "@every 1h", info.Spec)
+		assert.Equal(t, "original", info.Description)
+	})
+}
+
+// TestSchedule_At 測試 @at 單次排程
+func TestSchedule_At(t *testing.T) {
+	p := parser{}
+	s, err := p.parse("@at 2026-11-01T09:00:00Z")
+	require.NoError(t, err)
+
+	at := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, at, s.next(at.Add(-time.Hour)))
+	assert.True(t, s.next(at).IsZero())
+	assert.True(t, s.next(at.Add(time.Hour)).IsZero())
+
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	next := s.next(at.Add(-time.Hour).In(taipei))
+	assert.True(t, next.Equal(at))
+	assert.Equal(t, taipei, next.Location())
+
+	for _, spec := range []string{"@at", "@at tomorrow", "@at 2026-11-01 09:00"} {
+		_, err := p.parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// TestCron_OneShot 測試單次任務執行後移出排程並保留結果
+func TestCron_OneShot(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	c, err := New(Config{
+		Location:  time.UTC,
+		Clock:     clock,
+		Retention: 5 * time.Minute,
+	})
+	require.NoError(t, err)
+	defer cleanupCron(t, c)
+
+	start := clock.Now()
+	var atRuns, afterRuns, specRuns int32
+	atID, err := c.AddAt(start.Add(10*time.Minute), func() error {
+		atomic.AddInt32(&atRuns, 1)
+		return nil
+	}, WithName("at"))
+	require.NoError(t, err)
+	afterID, err := c.AddAfter(time.Minute, func() {
+		atomic.AddInt32(&afterRuns, 1)
+	})
+	require.NoError(t, err)
+	_, err = c.Add("@at 2026-01-01T00:02:00Z", func() {
+		atomic.AddInt32(&specRuns, 1)
+	})
+	require.NoError(t, err)
+
+	info, _ := c.Get(atID)
+	assert.Equal(t, "@at 2026-01-01T00:10:00Z", info.Spec)
+
+	c.Start()
+	for i := 0; i < 10; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	require.Eventually(t, func() bool {
+		info, _ := c.Get(atID)
+		return info.LastResult != nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&atRuns))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&afterRuns))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&specRuns))
+
+	// * 執行後仍可查詢結果
+	info, ok := c.Get(atID)
+	require.True(t, ok)
+	assert.Equal(t, TaskCompleted, info.LastResult.Status)
+	assert.True(t, info.Next.IsZero())
+	assert.Equal(t, start.Add(10*time.Minute), info.Prev)
+
+	// * 較早完成的單次任務已超過保留時間
+	_, ok = c.Get(afterID)
+	assert.False(t, ok)
+	assert.Len(t, c.List(), 1)
+
+	clock.Advance(5 * time.Minute)
+	_, ok = c.Get(atID)
+	assert.False(t, ok)
+	assert.Empty(t, c.List())
+
+	// * 移除後不殘留於依賴管理
+	c.depend.manager.mutex.RLock()
+	assert.Empty(t, c.depend.manager.list)
+	c.depend.manager.mutex.RUnlock()
+}
+
+// TestCron_OneShotPast 測試已過時間的單次任務
+func TestCron_OneShotPast(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	c, err := New(Config{
+		Location:  time.UTC,
+		Clock:     clock,
+		Retention: 5 * time.Minute,
+	})
+	require.NoError(t, err)
+	defer cleanupCron(t, c)
+
+	_, err = c.AddAt(clock.Now().Add(-time.Minute), func() {})
+	assert.EqualError(t, err, "time is in the past: 2025-12-31T23:59:00Z")
+	_, err = c.AddAfter(0, func() {})
+	assert.EqualError(t, err, "delay must be positive")
+	_, err = c.Add("@at 2020-01-01T00:00:00Z", func() {})
+	assert.EqualError(t, err, "time is in the past: 2020-01-01T00:00:00Z")
+	assert.Empty(t, c.List())
+
+	// * 啟動前已過時間的單次任務不執行，保留時間後移除
+	var runs int32
+	id, err := c.AddAfter(time.Minute, func() {
+		atomic.AddInt32(&runs, 1)
+	})
+	require.NoError(t, err)
+	err = c.Update(id, "@at 2020-01-01T00:00:00Z")
+	assert.EqualError(t, err, "time is in the past: 2020-01-01T00:00:00Z")
+	clock.Advance(2 * time.Minute)
+	c.Start()
+	clock.BlockUntil(1)
+
+	_, ok := c.Get(id)
+	assert.True(t, ok)
+	clock.Advance(5 * time.Minute)
+	assert.Eventually(t, func() bool {
+		_, ok := c.Get(id)
+		return !ok
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&runs))
+}
+
+// TestCron_ScheduleBounds 測試開始時間、結束時間與執行次數上限
+func TestCron_ScheduleBounds(t *testing.T) {
+	t.Run("start at", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		start := clock.Now()
+		cronID, err := c.Add("0 * * * *", func() {}, WithStartAt(start.Add(3*time.Hour)))
+		require.NoError(t, err)
+		everyID, err := c.Add("@every 1m", func() {}, WithStartAt(start.Add(10*time.Minute)))
+		require.NoError(t, err)
+		c.Start()
+		clock.BlockUntil(1)
+
+		// * 開始時間本身包含在內；固定間隔自開始時間起算
+		info, _ := c.Get(cronID)
+		assert.Equal(t, start.Add(3*time.Hour), info.Next)
+		info, _ = c.Get(everyID)
+		assert.Equal(t, start.Add(11*time.Minute), info.Next)
+	})
+
+	t.Run("end at and max runs", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		start := clock.Now()
+		var endRuns, maxRuns int32
+		endID, err := c.Add("@every 1m", func() {
+			atomic.AddInt32(&endRuns, 1)
+		}, WithEndAt(start.Add(3*time.Minute)))
+		require.NoError(t, err)
+		maxID, err := c.Add("@every 1m", func() {
+			atomic.AddInt32(&maxRuns, 1)
+		}, WithMaxRuns(2))
+		require.NoError(t, err)
+		_, err = c.Add("@every 1h", func() {})
+		require.NoError(t, err)
+		c.Start()
+
+		for i := 0; i < 5; i++ {
+			clock.BlockUntil(1)
+			clock.Advance(time.Minute)
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&endRuns) == 3 && atomic.LoadInt32(&maxRuns) == 2
+		}, time.Second, time.Millisecond)
+
+		for _, id := range []int64{endID, maxID} {
+			info, ok := c.Get(id)
+			require.True(t, ok)
+			assert.True(t, info.Next.IsZero())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		start := clock.Now()
+		_, err := c.Add("@every 1m", func() {},
+			WithStartAt(start.Add(time.Hour)),
+			WithEndAt(start),
+		)
+		assert.ErrorContains(t, err, "must be after start time")
+
+		_, err = c.Add("@every 1m", func() {}, WithMaxRuns(0))
+		assert.ErrorContains(t, err, "max runs must be positive")
+
+		id, err := c.Add("@every 1m", func() {}, WithStartAt(start.Add(time.Hour)))
+		require.NoError(t, err)
+		err = c.Update(id, "@every 1m", WithEndAt(start))
+		assert.ErrorContains(t, err, "must be after start time")
+	})
+}
+
+// TestSchedule_Hash 測試 H 欄位依名稱取得固定且分散的值
+func TestSchedule_Hash(t *testing.T) {
+	a := parser{hash: "billing"}
+	first, err := a.parse("H * * * *")
+	require.NoError(t, err)
+	second, err := a.parse("H * * * *")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	result := first.(*scheduleResult)
+	assert.True(t, result.hashed)
+	require.Len(t, result.minute.Values, 1)
+	assert.True(t, result.hour.All)
+
+	s, err := a.parse("H(0-29)/10 H(9-17) H * H")
+	require.NoError(t, err)
+	result = s.(*scheduleResult)
+	require.Len(t, result.minute.Values, 3)
+	assert.Less(t, result.minute.Values[0], 10)
+	assert.Equal(t, []int{result.minute.Values[0], result.minute.Values[0] + 10, result.minute.Values[0] + 20}, result.minute.Values)
+	require.Len(t, result.hour.Values, 1)
+	assert.True(t, result.hour.Values[0] >= 9 && result.hour.Values[0] <= 17)
+	require.Len(t, result.dom.Values, 1)
+	assert.True(t, result.dom.Values[0] >= 1 && result.dom.Values[0] <= 28)
+	require.Len(t, result.dow.Values, 1)
+	assert.True(t, result.dow.Values[0] >= 0 && result.dow.Values[0] <= 6)
+
+	// * 不同名稱分散於整個範圍
+	minutes := make(map[int]bool)
+	for i := 0; i < 200; i++ {
+		s, err := parser{hash: fmt.Sprintf("service-%d", i)}.parse("H * * * *")
+		require.NoError(t, err)
+		minutes[s.(*scheduleResult).minute.Values[0]] = true
+	}
+	assert.Greater(t, len(minutes), 40)
+
+	// * 英文縮寫中的 H 不視為雜湊
+	s, err = a.parse("0 0 * * THU")
+	require.NoError(t, err)
+	assert.Equal(t, []int{4}, s.(*scheduleResult).dow.Values)
+	assert.False(t, s.(*scheduleResult).hashed)
+
+	seconds := parser{seconds: true, hash: "billing"}
+	s, err = seconds.parse("H H * * * *")
+	require.NoError(t, err)
+	require.Len(t, s.(*scheduleResult).second.Values, 1)
+
+	for _, spec := range []string{
+		"H(30-10) * * * *",
+		"H(0-60) * * * *",
+		"H(0-5 * * * *",
+		"H/0 * * * *",
+		"H/x * * * *",
+		"1H * * * *",
+		"Hx * * * *",
+	} {
+		_, err := a.parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// TestCron_Hash 測試 H 欄位以任務名稱決定下一次執行
+func TestCron_Hash(t *testing.T) {
+	c, clock := createFakeClockCron(t)
+	defer cleanupCron(t, c)
+
+	firstID, err := c.Add("H * * * *", func() {}, WithName("billing"))
+	require.NoError(t, err)
+	secondID, err := c.Add("H * * * *", func() {}, WithName("billing"))
+	require.NoError(t, err)
+	unnamedID, err := c.Add("H * * * *", func() {})
+	require.NoError(t, err)
+	c.Start()
+	clock.BlockUntil(1)
+
+	expected, err := parser{hash: "billing"}.parse("H * * * *")
+	require.NoError(t, err)
+	first, _ := c.Get(firstID)
+	second, _ := c.Get(secondID)
+	assert.Equal(t, expected.next(clock.Now()), first.Next)
+	assert.Equal(t, first.Next, second.Next)
+
+	// * 未命名時以 ID 為雜湊來源
+	expected, err = parser{hash: strconv.FormatInt(unnamedID, 10)}.parse("H * * * *")
+	require.NoError(t, err)
+	unnamed, _ := c.Get(unnamedID)
+	assert.Equal(t, expected.next(clock.Now()), unnamed.Next)
+
+	// * 更名後重新計算
+	require.NoError(t, c.Update(secondID, "H * * * *", WithName("reports")))
+	expected, err = parser{hash: "reports"}.parse("H * * * *")
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		info, _ := c.Get(secondID)
+		return info.Next.Equal(expected.next(clock.Now()))
+	}, time.Second, time.Millisecond)
+}
+
+// TestCron_Jitter 測試隨機延遲反映於下一次執行且不累積偏移
+func TestCron_Jitter(t *testing.T) {
+	c, clock := createFakeClockCron(t)
+	defer cleanupCron(t, c)
+
+	start := clock.Now()
+	var runs int32
+	id, err := c.Add("@every 1h", func() {
+		atomic.AddInt32(&runs, 1)
+	}, WithName("billing"), WithJitter(10*time.Minute))
+	require.NoError(t, err)
+	sameID, err := c.Add("@every 1h", func() {}, WithName("billing"), WithJitter(10*time.Minute))
+	require.NoError(t, err)
+	c.Start()
+	clock.BlockUntil(1)
+
+	info, _ := c.Get(id)
+	same, _ := c.Get(sameID)
+	assert.Equal(t, info.Next, same.Next)
+	assert.False(t, info.Next.Before(start.Add(time.Hour)))
+	assert.True(t, info.Next.Before(start.Add(time.Hour+10*time.Minute)))
+
+	clock.Advance(info.Next.Sub(clock.Now()))
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&runs) == 1
+	}, time.Second, time.Millisecond)
+
+	// * 自原排程時間起算
+	require.Eventually(t, func() bool {
+		info, _ = c.Get(id)
+		return info.Next.After(clock.Now())
+	}, time.Second, time.Millisecond)
+	assert.False(t, info.Next.Before(start.Add(2*time.Hour)))
+	assert.True(t, info.Next.Before(start.Add(2*time.Hour+10*time.Minute)))
+
+	_, err = c.Add("@every 1h", func() {}, WithJitter(0))
+	assert.ErrorContains(t, err, "jitter must be positive")
+}
+
+// TestCron_DependencyValidation 測試新增與更新時拒絕不存在的依賴與循環
+func TestCron_DependencyValidation(t *testing.T) {
+	c := createTestCron(t)
+	defer cleanupCron(t, c)
+
+	action := func() error { return nil }
+	aID, err := c.Add("@every 1m", action)
+	require.NoError(t, err)
+	bID, err := c.Add("@every 1m", action, WithDependencies(Wait{ID: aID}))
+	require.NoError(t, err)
+	cID, err := c.Add("@every 1m", action, WithDependencies(Wait{ID: bID}))
+	require.NoError(t, err)
+
+	var depErr *DependencyError
+	_, err = c.Add("@every 1m", action, WithDependencies(Wait{ID: 42}))
+	require.ErrorAs(t, err, &depErr)
+	assert.Equal(t, int64(42), depErr.Dependency)
+	assert.Empty(t, depErr.Cycle)
+	assert.ErrorContains(t, err, "dependence Task not found: 42")
+
+	_, err = c.Add("@every 1m", action, []int64{aID, 42})
+	assert.ErrorAs(t, err, &depErr)
+
+	// * 更新造成循環時拒絕並保留原依賴
+	err = c.Update(aID, "@every 1m", WithDependencies(Wait{ID: cID}))
+	require.ErrorAs(t, err, &depErr)
+	assert.Equal(t, []int64{aID, cID, bID, aID}, depErr.Cycle)
+	assert.EqualError(t, err, fmt.Sprintf("dependency cycle: %d -> %d -> %d -> %d", aID, cID, bID, aID))
+	info, _ := c.Get(aID)
+	assert.Empty(t, info.Dependencies)
+
+	err = c.Update(bID, "@every 1m", WithDependencies(Wait{ID: bID}))
+	require.ErrorAs(t, err, &depErr)
+	assert.Equal(t, []int64{bID, bID}, depErr.Cycle)
+
+	require.NoError(t, c.ValidateGraph())
+
+	// * 批次載入時延後檢查，由 ValidateGraph 一次回報
+	_, err = c.Add("@every 1m", action, WithDeferredValidation(), WithDependencies(Wait{ID: 99}))
+	require.NoError(t, err)
+	require.NoError(t, c.Update(aID, "@every 1m", WithDeferredValidation(), WithDependencies(Wait{ID: cID})))
+
+	err = c.ValidateGraph()
+	require.Error(t, err)
+	var errs []*DependencyError
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		require.ErrorAs(t, e, &depErr)
+		errs = append(errs, depErr)
+	}
+	require.Len(t, errs, 2)
+	assert.Equal(t, int64(99), errs[0].Dependency)
+	assert.Equal(t, []int64{aID, cID, bID, aID}, errs[1].Cycle)
+
+	// * 移除依賴任務後成為不存在的依賴
+	require.NoError(t, c.Update(aID, "@every 1m", WithDependencies()))
+	c.Remove(bID)
+	err = c.ValidateGraph()
+	require.ErrorAs(t, err, &depErr)
+	assert.ErrorContains(t, err, fmt.Sprintf("dependence Task not found: %d (required by %d)", bID, cID))
+}
+
+// TestCron_RunScopedDependencies 測試依賴僅由本次排程區間內的前置執行滿足
+func TestCron_RunScopedDependencies(t *testing.T) {
+	// * 推進時間並等待排程迴圈處理完畢
+	advance := func(clock *FakeClock, d time.Duration) {
+		for end := clock.Now().Add(d); clock.Now().Before(end); {
+			clock.BlockUntil(1)
+			clock.Advance(30 * time.Minute)
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	t.Run("daily chain", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		var parentRuns, childRuns int32
+		parentID, err := c.Add("0 1 * * *", func() error {
+			atomic.AddInt32(&parentRuns, 1)
+			return nil
+		}, WithName("extract"))
+		require.NoError(t, err)
+		_, err = c.Add("0 2 * * *", func() error {
+			atomic.AddInt32(&childRuns, 1)
+			return nil
+		}, WithName("load"), WithDependencies(Wait{ID: parentID, Delay: 3 * time.Hour}))
+		require.NoError(t, err)
+		c.Start()
+
+		advance(clock, 3*time.Hour)
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&childRuns) == 1
+		}, time.Second, time.Millisecond)
+
+		// * 前置任務當日未執行，不以前一日的結果放行
+		require.NoError(t, c.Pause(parentID))
+		advance(clock, 24*time.Hour)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&parentRuns))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&childRuns))
+
+		done, err := c.RunNow(parentID)
+		require.NoError(t, err)
+		<-done
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&childRuns) == 2
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("registered after upstream ran", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		parentID, err := c.Add("0 6 * * *", func() error { return nil })
+		require.NoError(t, err)
+		c.Start()
+		clock.BlockUntil(1)
+		done, err := c.RunNow(parentID)
+		require.NoError(t, err)
+		<-done
+
+		// * 加入前的前置執行不採計
+		var childRuns int32
+		_, err = c.Add("@every 1h", func() error {
+			atomic.AddInt32(&childRuns, 1)
+			return nil
+		}, WithDependencies(Wait{ID: parentID, Delay: 2 * time.Hour}))
+		require.NoError(t, err)
+
+		advance(clock, 90*time.Minute)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&childRuns))
+
+		done, err = c.RunNow(parentID)
+		require.NoError(t, err)
+		<-done
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&childRuns) == 1
+		}, time.Second, time.Millisecond)
+	})
+}
+
+// TestCron_Workflow 測試流程步驟依序執行並回報整體結果
+func TestCron_Workflow(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		var mu sync.Mutex
+		var order []string
+		runIDs := make(map[string]int64)
+		step := func(name string, after ...string) Step {
+			return Step{
+				Name:  name,
+				After: after,
+				Action: func(ctx context.Context) error {
+					id, _ := WorkflowRunID(ctx)
+					mu.Lock()
+					defer mu.Unlock()
+					order = append(order, name)
+					runIDs[name] = id
+					return nil
+				},
+			}
+		}
+
+		id, err := c.AddWorkflow("@every 1m", Workflow{
+			Name: "etl",
+			Steps: []Step{
+				step("report", "load"),
+				step("load", "transform"),
+				step("transform", "extract"),
+				step("extract"),
+			},
+		})
+		require.NoError(t, err)
+		info, _ := c.Get(id)
+		assert.Equal(t, "etl", info.Name)
+		_, ok := c.LastWorkflowRun(id)
+		assert.False(t, ok)
+
+		c.Start()
+		for i := 0; i < 2; i++ {
+			clock.BlockUntil(1)
+			clock.Advance(time.Minute)
+			require.Eventually(t, func() bool {
+				run, ok := c.LastWorkflowRun(id)
+				return ok && run.ID == int64(i+1)
+			}, time.Second, time.Millisecond)
+		}
+
+		run, _ := c.LastWorkflowRun(id)
+		assert.Equal(t, TaskCompleted, run.Status)
+		assert.Len(t, run.Steps, 4)
+		for _, result := range run.Steps {
+			assert.Equal(t, TaskCompleted, result.Status)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{
+			"extract", "transform", "load", "report",
+			"extract", "transform", "load", "report",
+		}, order)
+		for _, runID := range runIDs {
+			assert.Equal(t, int64(2), runID)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		c, _ := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		var loaded, cleaned int32
+		id, err := c.AddWorkflow("@daily", Workflow{
+			Name: "etl",
+			Steps: []Step{
+				{Name: "extract", Action: func(context.Context) error { return errors.New("source offline") }},
+				{Name: "load", After: []string{"extract"}, Action: func(context.Context) error {
+					atomic.AddInt32(&loaded, 1)
+					return nil
+				}},
+				{Name: "cleanup", After: []string{"extract"}, State: Skip, Action: func(context.Context) error {
+					atomic.AddInt32(&cleaned, 1)
+					return nil
+				}},
+			},
+		})
+		require.NoError(t, err)
+		c.Start()
+
+		done, err := c.RunNow(id)
+		require.NoError(t, err)
+		result := <-done
+		assert.Equal(t, TaskFailed, result.Status)
+		assert.ErrorContains(t, result.Error, "workflow steps failed: extract, load")
+
+		run, ok := c.LastWorkflowRun(id)
+		require.True(t, ok)
+		assert.Equal(t, TaskFailed, run.Status)
+		assert.Equal(t, TaskFailed, run.Steps["extract"].Status)
+		assert.Equal(t, TaskFailed, run.Steps["load"].Status)
+		assert.Equal(t, TaskCompleted, run.Steps["cleanup"].Status)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&loaded))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&cleaned))
+
+		c.Remove(id)
+		_, ok = c.LastWorkflowRun(id)
+		assert.False(t, ok)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		started := make(chan struct{})
+		cancelled := make(chan struct{})
+		id, err := c.AddWorkflow("@daily", Workflow{
+			Name: "etl",
+			Steps: []Step{
+				{Name: "extract", Action: func(ctx context.Context) error {
+					close(started)
+					<-ctx.Done()
+					close(cancelled)
+					return ctx.Err()
+				}},
+				{Name: "load", After: []string{"extract"}, Delay: time.Hour, Action: func(context.Context) error {
+					return nil
+				}},
+			},
+		}, WithTimeout(time.Minute))
+		require.NoError(t, err)
+		c.Start()
+
+		done, err := c.RunNow(id)
+		require.NoError(t, err)
+		<-started
+		clock.Advance(time.Minute)
+		assert.ErrorContains(t, (<-done).Error, "task timeout")
+
+		// * 執行中的步驟一併取消，流程等待所有步驟結束
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("running step was not cancelled")
+		}
+		require.Eventually(t, func() bool {
+			_, ok := c.LastWorkflowRun(id)
+			return ok
+		}, time.Second, time.Millisecond)
+		run, _ := c.LastWorkflowRun(id)
+		assert.Equal(t, TaskFailed, run.Status)
+		assert.Equal(t, TaskFailed, run.Steps["extract"].Status)
+		assert.Equal(t, TaskFailed, run.Steps["load"].Status)
+	})
+
+	t.Run("slow upstream", func(t *testing.T) {
+		c, clock := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		id, err := c.AddWorkflow("@daily", Workflow{
+			Name: "etl",
+			Steps: []Step{
+				{Name: "extract", Action: func(context.Context) error {
+					close(started)
+					<-release
+					return nil
+				}},
+				{Name: "load", After: []string{"extract"}, Action: func(context.Context) error {
+					return nil
+				}},
+			},
+		})
+		require.NoError(t, err)
+		c.Start()
+
+		done, err := c.RunNow(id)
+		require.NoError(t, err)
+		<-started
+
+		// * 未設定 Delay 的步驟不受獨立任務的 1 分鐘等待上限限制
+		time.Sleep(20 * time.Millisecond)
+		clock.Advance(5 * time.Minute)
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+
+		assert.Equal(t, TaskCompleted, (<-done).Status)
+		run, _ := c.LastWorkflowRun(id)
+		assert.Equal(t, TaskCompleted, run.Steps["load"].Status)
+	})
+
+	t.Run("remove during run", func(t *testing.T) {
+		c, _ := createFakeClockCron(t)
+		defer cleanupCron(t, c)
+
+		started := make(chan struct{})
+		var loaded int32
+		id, err := c.AddWorkflow("@daily", Workflow{
+			Name: "etl",
+			Steps: []Step{
+				{Name: "extract", Action: func(ctx context.Context) error {
+					close(started)
+					<-ctx.Done()
+					return ctx.Err()
+				}},
+				{Name: "load", After: []string{"extract"}, State: Skip, Delay: time.Hour, Action: func(context.Context) error {
+					atomic.AddInt32(&loaded, 1)
+					return nil
+				}},
+			},
+		})
+		require.NoError(t, err)
+		c.Start()
+
+		done, err := c.RunNow(id)
+		require.NoError(t, err)
+		<-started
+		c.Remove(id)
+
+		// * 等待中的步驟不需等到逾時
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("workflow run did not finish after Remove")
+		}
+		assert.Equal(t, int32(0), atomic.LoadInt32(&loaded))
+		c.depend.manager.mutex.RLock()
+		assert.Empty(t, c.depend.manager.list)
+		c.depend.manager.mutex.RUnlock()
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c := createTestCron(t)
+		defer cleanupCron(t, c)
+
+		action := func(context.Context) error { return nil }
+		tests := []struct {
+			steps    []Step
+			errorMsg string
+		}{
+			{nil, "workflow has no steps"},
+			{[]Step{{Action: action}}, "workflow step name is empty"},
+			{[]Step{{Name: "a", Action: action}, {Name: "a", Action: action}}, "duplicate workflow step: a"},
+			{[]Step{{Name: "a"}}, "workflow step action is nil: a"},
+			{[]Step{{Name: "a", After: []string{"b"}, Action: action}}, "workflow step a depends on unknown step: b"},
+			{[]Step{{Name: "a", After: []string{"b"}, Action: action}, {Name: "b", After: []string{"a"}, Action: action}}, "workflow cycle:"},
+		}
+		for _, tt := range tests {
+			_, err := c.AddWorkflow("@daily", Workflow{Name: "broken", Steps: tt.steps})
+			assert.ErrorContains(t, err, tt.errorMsg)
+		}
+
+		_, err := c.AddWorkflow("invalid", Workflow{Steps: []Step{{Name: "a", Action: action}}})
+		assert.ErrorContains(t, err, "failed to parse")
+		assert.Empty(t, c.List())
+	})
 }
@@ -16,11 +16,11 @@ func newDepend() *depend {
 	return &depend{
 		manager:  newDependManager(),
 		stopChan: make(chan struct{}),
-		queue:    make(chan Wait, 1024),
+		queue:    make(chan taskWait, 1024),
 	}
 }
 
-func (d *depend) start() {
+func (d *depend) start(ctx context.Context) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
@@ -28,6 +28,8 @@ func (d *depend) start() {
 		return
 	}
 	d.running = true
+	d.ctx = ctx
+	d.stopChan = make(chan struct{})
 
 	for i := 0; i < maxWorker; i++ {
 		d.wait.Add(1)
@@ -61,68 +63,142 @@ func (d *depend) worker() {
 	}
 }
 
-func (d *depend) addWait(id int64, delay time.Duration, state WaitState) {
+func (d *depend) addWait(id int64, delay time.Duration, state WaitState, done chan TaskResult) {
 	var timeout = 1 * time.Minute
 	if delay > 0 {
 		timeout = delay
 	}
-	d.queue <- Wait{
-		ID:    id,
-		Delay: timeout,
-		State: state,
-	}
+
+	d.spawn(taskWait{
+		Wait: Wait{
+			ID:    id,
+			Delay: timeout,
+			State: state,
+		},
+		done: done,
+	})
 }
 
-// * Worker 執行的排序（v0.4.0 對 Worker 數進行了限制）
-func (d *depend) runAfter(queue Wait) {
+// * 流程步驟的依賴等待：delay 為 0 時不設逾時，僅受流程執行的 context 限制
+func (d *depend) addStepWait(parent context.Context, id int64, delay time.Duration, state WaitState, done chan TaskResult) {
+	d.spawn(taskWait{
+		Wait: Wait{
+			ID:    id,
+			Delay: delay,
+			State: state,
+		},
+		done:   done,
+		parent: parent,
+	})
+}
+
+func (d *depend) spawn(queue taskWait) {
+	d.mutex.RLock()
+	ctx, stop := d.ctx, d.stopChan
+	d.wait.Add(1)
+	d.mutex.RUnlock()
+
+	// * 等待依賴期間不佔用 Worker，就緒後才排入佇列
+	go func() {
+		defer d.wait.Done()
+		d.await(ctx, stop, queue)
+	}()
+}
+
+func (d *depend) await(ctx context.Context, stop chan struct{}, queue taskWait) {
+	d.manager.mutex.RLock()
 	task, isExist := d.manager.list[queue.ID]
+	d.manager.mutex.RUnlock()
 	if !isExist {
 		d.logger.Error(
 			"Task not found",
 			"ID", int(queue.ID),
 		)
+		if queue.done != nil {
+			close(queue.done)
+		}
 		return
 	}
 
-	task.mutex.RLock()
-	status := task.state
-	task.mutex.RUnlock()
+	waitCtx := ctx
+	if queue.parent != nil {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		release := context.AfterFunc(queue.parent, cancel)
+		defer release()
+	}
 
-	if status == TaskRunning || status == TaskCompleted {
+	runs, err := d.manager.wait(waitCtx, queue.ID, queue.Delay)
+	// * 排程已停止時捨棄
+	if ctx.Err() != nil {
+		if queue.done != nil {
+			close(queue.done)
+		}
+		d.done(task)
 		return
 	}
-
-	if err := d.manager.wait(queue.ID, queue.Delay); err != nil {
+	if err != nil {
 		result := taskResult{
 			ID:     queue.ID,
 			status: TaskFailed,
-			start:  time.Now(),
-			end:    time.Now(),
+			start:  d.clock.Now(),
+			end:    d.clock.Now(),
 			error:  err,
 		}
+		d.manager.consume(task, runs)
 		d.manager.update(result)
+		notify(queue.done, result)
 		d.logger.Error(
 			"Dependence Task failed",
 			"ID", int(queue.ID),
 			"error", err,
 		)
+		d.done(task)
 		return
 	}
 
-	d.run(task)
+	queue.runs = runs
+	select {
+	case d.queue <- queue:
+	case <-stop:
+		if queue.done != nil {
+			close(queue.done)
+		}
+		d.done(task)
+	}
 }
 
-func (d *depend) run(task *task) {
-	start := time.Now()
+// * Worker 執行的排序（v0.4.0 對 Worker 數進行了限制）
+func (d *depend) runAfter(queue taskWait) {
+	d.manager.mutex.RLock()
+	task, isExist := d.manager.list[queue.ID]
+	d.manager.mutex.RUnlock()
+	if !isExist {
+		if queue.done != nil {
+			close(queue.done)
+		}
+		return
+	}
+	// * 重複執行由 OverlapPolicy 控制
+	defer d.done(task)
+
+	d.run(task, queue.done, queue.runs)
+}
+
+func (d *depend) run(task *task, done chan TaskResult, runs map[int64]int) {
+	start := d.clock.Now()
 
 	task.mutex.Lock()
 	task.state = TaskRunning
+	attempt, policy, description := task.attempt, task.retry, task.description
 	task.mutex.Unlock()
 
 	d.logger.Info(
 		"Task started",
 		"ID", int(task.ID),
-		"description", task.description,
+		"description", description,
+		"attempt", attempt,
 	)
 
 	var taskError error
@@ -139,35 +215,21 @@ func (d *depend) run(task *task) {
 			}
 		}()
 
-		if task.delay > 0 {
-			ctx, cancel := context.WithTimeout(context.Background(), task.delay)
-			defer cancel()
-
-			done := make(chan error, 1)
-			go func() {
-				done <- task.action()
-			}()
-
-			select {
-			case err := <-done:
-				taskError = err
-			case <-ctx.Done():
-				taskError = fmt.Errorf("task timeout %d", task.delay)
-				if task.onDelay != nil {
-					task.onDelay()
-				}
-			}
-		} else {
-			taskError = task.action()
-		}
+		taskError = task.execute(d.ctx, d.clock)
 	}()
 
-	end := time.Now()
+	end := d.clock.Now()
 	duration := end.Sub(start)
 
 	status := TaskCompleted
+	var delay time.Duration
+	var retry bool
 	if taskError != nil {
 		status = TaskFailed
+		// * 重試期間依賴此任務者持續等待
+		if delay, retry = policy.backoff(attempt, taskError); retry {
+			status = TaskRetrying
+		}
 	}
 
 	result := taskResult{
@@ -177,17 +239,27 @@ func (d *depend) run(task *task) {
 		end:      end,
 		duration: duration,
 		error:    taskError,
+		attempt:  attempt,
 	}
 
+	// * 重試沿用同一批依賴執行
+	if status != TaskRetrying {
+		d.manager.consume(task, runs)
+	}
 	d.manager.update(result)
+	notify(done, result)
 
 	if taskError != nil {
 		d.logger.Error(
 			"Task failed",
 			"ID", int(task.ID),
 			"duration", duration,
+			"attempt", attempt,
 			"error", taskError,
 		)
+		if retry {
+			d.retry(task, attempt, delay)
+		}
 	} else {
 		d.logger.Info(
 			"Task completed",
@@ -9,7 +9,7 @@ import (
 func newDependManager() *dependManager {
 	return &dependManager{
 		list:    make(map[int64]*task),
-		waiting: make(map[int64][]*task),
+		waiting: make(map[int64][]chan struct{}),
 	}
 }
 
@@ -20,13 +20,26 @@ func (m *dependManager) add(t *task) {
 	m.list[t.ID] = t
 
 	t.mutex.RLock()
-	hasAfter := len(t.after) > 0
+	after := t.after
 	t.mutex.RUnlock()
+	hasAfter := len(after) > 0
+	baseline := m.baseline(after)
 
 	// * 存在依賴任務
 	if hasAfter {
 		t.startChan = make(chan struct{}, 1)
 		t.doneChan = make(chan taskResult, 1)
+		t.mutex.Lock()
+		// * 任務於多處建立，未配置時於此補上
+		if t.consumed == nil {
+			t.consumed = make(map[int64]int, len(baseline))
+		}
+		for id, count := range baseline {
+			if _, isExist := t.consumed[id]; !isExist {
+				t.consumed[id] = count
+			}
+		}
+		t.mutex.Unlock()
 	}
 
 	if t.state == 0 {
@@ -34,16 +47,37 @@ func (m *dependManager) add(t *task) {
 	}
 }
 
+func (m *dependManager) remove(tasks []*task) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// * 一併取消執行中的任務
+	for _, t := range tasks {
+		delete(m.list, t.ID)
+		t.cancel()
+	}
+	// * 喚醒等待已移除任務者，重新檢查後結束
+	for _, t := range tasks {
+		for _, signal := range m.waiting[t.ID] {
+			select {
+			case signal <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
 func (m *dependManager) check(id int64) taskState {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
 	task, isExist := m.list[id]
-	// * 任務不存在
+	// * 任務不存在，等待者立即結束
 	if !isExist {
 		return taskState{
-			done:  false,
-			error: fmt.Errorf("task not found: %d", id),
+			done:   false,
+			failed: &id,
+			error:  fmt.Errorf("task not found: %d", id),
 		}
 	}
 
@@ -51,6 +85,7 @@ func (m *dependManager) check(id int64) taskState {
 	defer task.mutex.RUnlock()
 
 	var waiting []Wait
+	runs := make(map[int64]int, len(task.after))
 
 	for _, e := range task.after {
 		afterTask, isExist := m.list[e.ID]
@@ -64,9 +99,16 @@ func (m *dependManager) check(id int64) taskState {
 		}
 
 		afterTask.mutex.RLock()
-		status := afterTask.state
+		status, count := afterTask.state, afterTask.runs
 		afterTask.mutex.RUnlock()
 
+		// * 僅採計此任務上次執行後才結束的依賴執行
+		if count <= task.consumed[e.ID] {
+			waiting = append(waiting, e)
+			continue
+		}
+		runs[e.ID] = count
+
 		// * 依賴任務執行錯誤處理
 		if status == TaskFailed {
 			if e.State == Stop {
@@ -74,6 +116,7 @@ func (m *dependManager) check(id int64) taskState {
 					done:   false,
 					failed: &e.ID,
 					error:  fmt.Errorf("dependence Task is failed: %d", id),
+					runs:   runs,
 				}
 			}
 			continue
@@ -96,31 +139,118 @@ func (m *dependManager) check(id int64) taskState {
 
 	return taskState{
 		done: true,
+		runs: runs,
+	}
+}
+
+// * 本次執行結束，記錄已採計的依賴執行
+func (m *dependManager) consume(t *task, runs map[int64]int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.consumed == nil {
+		t.consumed = make(map[int64]int, len(runs))
+	}
+	for id, count := range runs {
+		if count > t.consumed[id] {
+			t.consumed[id] = count
+		}
 	}
 }
 
-// TODO: 後續改寫觸發的方式
-func (m *dependManager) wait(id int64, timeout time.Duration) error {
-	// * context 超時控制
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+// * 依賴任務目前的執行次數，需持有 mutex；新加入的依賴僅採計之後的執行
+func (m *dependManager) baseline(after []Wait) map[int64]int {
+	consumed := make(map[int64]int, len(after))
+	for _, e := range after {
+		if afterTask, isExist := m.list[e.ID]; isExist {
+			afterTask.mutex.RLock()
+			consumed[e.ID] = afterTask.runs
+			afterTask.mutex.RUnlock()
+		}
+	}
+	return consumed
+}
+
+// * 由 update 於依賴任務狀態變更時喚醒，等待期間不佔用 Worker
+func (m *dependManager) wait(parent context.Context, id int64, timeout time.Duration) (map[int64]int, error) {
+	// * context 超時控制，timeout 為 0 時不設逾時
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = withTimeout(m.clock, parent, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
 	defer cancel()
 
+	// * 先登記再檢查，避免漏接兩者之間的通知
+	signal, after := m.watch(id)
+	defer m.unwatch(signal, after)
+
 	for {
 		result := m.check(id)
 		// * 依賴任務接完成
 		if result.done {
-			return nil
+			return result.runs, nil
 		}
 
 		// * 依賴任務失敗
 		if result.failed != nil {
-			return fmt.Errorf("dependence Task failed: %d, %s", *result.failed, result.error.Error())
+			return result.runs, fmt.Errorf("dependence Task failed: %d, %s", *result.failed, result.error.Error())
 		}
 
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("timeout waiting for dependencies: %s", result.error.Error())
-		case <-time.After(1 * time.Millisecond):
+			if parent.Err() != nil {
+				return nil, parent.Err()
+			}
+			return nil, fmt.Errorf("timeout waiting for dependencies: %s", result.error.Error())
+		case <-signal:
+		}
+	}
+}
+
+// * 於各依賴任務登記通知
+// * 回傳登記的依賴 ID，供 unwatch 僅處理對應項目
+func (m *dependManager) watch(id int64) (chan struct{}, []int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	signal := make(chan struct{}, 1)
+	task, isExist := m.list[id]
+	if !isExist {
+		return signal, nil
+	}
+
+	task.mutex.RLock()
+	defer task.mutex.RUnlock()
+	after := make([]int64, len(task.after))
+	for i, e := range task.after {
+		after[i] = e.ID
+		m.waiting[e.ID] = append(m.waiting[e.ID], signal)
+	}
+	return signal, after
+}
+
+func (m *dependManager) unwatch(signal chan struct{}, after []int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, afterID := range after {
+		signals, isExist := m.waiting[afterID]
+		if !isExist {
+			continue
+		}
+		for i, e := range signals {
+			if e == signal {
+				signals = append(signals[:i], signals[i+1:]...)
+				break
+			}
+		}
+		if len(signals) == 0 {
+			delete(m.waiting, afterID)
+		} else {
+			m.waiting[afterID] = signals
 		}
 	}
 }
@@ -133,6 +263,10 @@ func (m *dependManager) update(result taskResult) {
 		task.mutex.Lock()
 		task.state = result.status
 		task.result = &result
+		task.runs++
+		if result.status != TaskCompleted {
+			task.failures++
+		}
 		task.mutex.Unlock()
 
 		// * 完成通知
@@ -140,5 +274,13 @@ func (m *dependManager) update(result taskResult) {
 		case task.doneChan <- result:
 		default:
 		}
+
+		// * 喚醒等待此任務的依賴者重新檢查
+		for _, signal := range m.waiting[result.ID] {
+			select {
+			case signal <- struct{}{}:
+			default:
+			}
+		}
 	}
 }
@@ -0,0 +1,69 @@
+//go:build unix
+
+package core
+
+import (
+	"context"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// * 行程已使用的 CPU 時間（使用者 + 系統）
+func cpuTime(b *testing.B) time.Duration {
+	b.Helper()
+
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		b.Fatal(err)
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
+
+// BenchmarkDependManager_Pending 量測 1,000 個等待中依賴者的閒置 CPU 使用量，ns/op 為全部解除等待的耗時
+func BenchmarkDependManager_Pending(b *testing.B) {
+	const dependents = 1000
+	const idle = 100 * time.Millisecond
+
+	var idleCPU time.Duration
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		m := newDependManager()
+		m.clock = realClock{}
+		m.add(&task{ID: 1})
+		for id := int64(2); id <= dependents+1; id++ {
+			m.add(&task{ID: id, after: []Wait{{ID: 1}}})
+		}
+
+		var wg sync.WaitGroup
+		for id := int64(2); id <= dependents+1; id++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := m.wait(context.Background(), id, time.Minute); err != nil {
+					b.Error(err)
+				}
+			}()
+		}
+		for {
+			m.mutex.RLock()
+			ready := len(m.waiting[1]) == dependents
+			m.mutex.RUnlock()
+			if ready {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		start := cpuTime(b)
+		time.Sleep(idle)
+		idleCPU += cpuTime(b) - start
+		b.StartTimer()
+
+		m.update(taskResult{ID: 1, status: TaskCompleted})
+		wg.Wait()
+	}
+
+	b.ReportMetric(idleCPU.Seconds()*1000/float64(b.N)/idle.Seconds(), "idle-cpu-ms/s")
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"container/heap"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"log/syslog"
@@ -16,6 +17,16 @@ func New(c Config) (*cron, error) {
 		location = c.Location
 	}
 
+	retention := defaultRetention
+	if c.Retention > 0 {
+		retention = c.Retention
+	}
+
+	var clock Clock = realClock{}
+	if c.Clock != nil {
+		clock = c.Clock
+	}
+
 	var logger *slog.Logger
 	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_LOCAL0, "goCron")
 	if err != nil {
@@ -28,21 +39,38 @@ func New(c Config) (*cron, error) {
 		}))
 	}
 
-	depend := newDepend();
+	depend := newDepend()
 	depend.logger = logger
+	depend.clock = clock
+	depend.manager.clock = clock
 
 	cron := &cron{
 		heap:      make(taskHeap, 0),
-		parser:    parser{},
+		tasks:     make(map[int64]*task),
+		workflows: make(map[int64]*workflow),
+		parser: parser{
+			seconds: c.Seconds,
+			dayAnd:  c.DayMatch == DayAnd,
+			dst:     c.DST,
+			clock:   clock,
+		},
 		stop:      make(chan struct{}),
 		add:       make(chan *task),
-		remove:    make(chan int64),
-		removeAll: make(chan struct{}),
+		remove:    make(chan *task),
+		removeAll: make(chan []*task),
+		retry:     make(chan taskRetry),
+		resume:    make(chan taskResume),
+		update:    make(chan taskUpdate),
 		location:  location,
 		running:   false,
 		depend:    depend,
 		logger:    logger,
+		clock:     clock,
+		misfire:   c.Misfire,
+		retention: retention,
 	}
+	depend.done = cron.finish
+	depend.retry = cron.scheduleRetry
 
 	return cron, nil
 }
@@ -53,25 +81,45 @@ func (c *cron) Start() {
 
 	if !c.running {
 		c.running = true
-		c.depend.start()
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+		c.depend.start(c.ctx)
 
 		go func() {
-			now := time.Now().In(c.location)
+			now := c.clock.Now().In(c.location)
 
+			active := c.heap[:0]
 			for _, entry := range c.heap {
-				entry.next = entry.schedule.next(now)
+				// * 啟用錯過判定時保留原排程，以偵測延後啟動或停機期間錯過的執行
+				entry.mutex.Lock()
+				if entry.misfire.Threshold <= 0 || entry.next.IsZero() {
+					entry.next = entry.nextRun(now)
+				}
+				entry.mutex.Unlock()
+				if entry.due().IsZero() {
+					c.logger.Warn(
+						"Task has no next run",
+						"ID", int(entry.ID),
+					)
+					// * 啟動前已過時間的單次任務於保留時間後移除
+					if entry.expired(now) {
+						c.expire(entry)
+					}
+					continue
+				}
+				active = append(active, entry)
 			}
+			c.heap = active
 			heap.Init(&c.heap)
 
 			for {
-				var timer *time.Timer
+				var timer Timer
 				var timerC <-chan time.Time
 
-				if len(c.heap) == 0 || c.heap[0].next.IsZero() {
+				if len(c.heap) == 0 || c.heap[0].due().IsZero() {
 					timerC = nil
 				} else {
-					timer = time.NewTimer(c.heap[0].next.Sub(now))
-					timerC = timer.C
+					timer = c.clock.NewTimer(c.heap[0].due().Sub(now))
+					timerC = timer.C()
 				}
 
 				for {
@@ -80,19 +128,58 @@ func (c *cron) Start() {
 						// * 時間觸發
 						now = now.In(c.location)
 
-						for len(c.heap) > 0 && (c.heap[0].next.Before(now) || c.heap[0].next.Equal(now)) {
+						for len(c.heap) > 0 && !c.heap[0].due().After(now) {
 							e := heap.Pop(&c.heap).(*task)
 
 							if !e.enable {
 								continue
 							}
 
-							c.run(e)
+							// * 暫停中不執行，記錄錯過的排程供恢復時補執行
+							e.mutex.Lock()
+							paused := e.paused
+							if paused && !e.next.IsZero() && !e.next.After(now) {
+								e.pauseMissed = true
+								e.next = e.nextRun(now)
+							}
+							e.mutex.Unlock()
+							if paused {
+								e.retryAt = time.Time{}
+								if !e.due().IsZero() {
+									heap.Push(&c.heap, e)
+								}
+								continue
+							}
 
-							e.prev = e.next
-							e.next = e.schedule.next(now)
-							if !e.next.IsZero() {
+							// * 重試早於下一次排程時執行重試，否則由排程取代
+							retry := !e.retryAt.IsZero() && (e.next.IsZero() || e.next.After(now))
+							e.retryAt = time.Time{}
+							if retry {
+								c.run(e, nil)
+							} else {
+								e.mutex.Lock()
+								e.attempt = 1
+								e.mutex.Unlock()
+
+								c.trigger(e, now)
+
+								e.mutex.Lock()
+								e.prev = e.next
+								e.next = e.nextRun(now)
+								e.mutex.Unlock()
+							}
+							if !e.due().IsZero() {
 								heap.Push(&c.heap, e)
+							} else if e.bounded() {
+								c.logger.Info(
+									"Task finished",
+									"ID", int(e.ID),
+								)
+							} else {
+								c.logger.Warn(
+									"Task has no next run",
+									"ID", int(e.ID),
+								)
 							}
 						}
 
@@ -101,34 +188,108 @@ func (c *cron) Start() {
 						if timer != nil {
 							timer.Stop()
 						}
-						now = time.Now().In(c.location)
-						newEntry.next = newEntry.schedule.next(now)
-						heap.Push(&c.heap, newEntry)
+						now = c.clock.Now().In(c.location)
+						newEntry.mutex.Lock()
+						newEntry.next = newEntry.nextRun(now)
+						newEntry.mutex.Unlock()
 						c.depend.manager.add(newEntry)
+						if newEntry.next.IsZero() {
+							c.logger.Warn(
+								"Task has no next run",
+								"ID", int(newEntry.ID),
+							)
+							if newEntry.expired(now) {
+								c.expire(newEntry)
+							}
+							break
+						}
+						heap.Push(&c.heap, newEntry)
+
+					case r := <-c.retry:
+						// * 重試排入 heap
+						if timer != nil {
+							timer.Stop()
+						}
+						now = c.clock.Now().In(c.location)
+						if !r.task.enable {
+							break
+						}
+						r.task.retryAt = r.at
+						c.reschedule(r.task)
+
+					case u := <-c.update:
+						// * 更新排程，保留 ID 與執行紀錄
+						if timer != nil {
+							timer.Stop()
+						}
+						now = c.clock.Now().In(c.location)
+						if !u.task.enable {
+							break
+						}
+						u.task.mutex.Lock()
+						u.task.schedule = u.schedule
+						u.task.next = u.task.nextRun(now)
+						u.task.mutex.Unlock()
+						if u.task.due().IsZero() {
+							c.logger.Warn(
+								"Task has no next run",
+								"ID", int(u.task.ID),
+							)
+						}
+						c.reschedule(u.task)
+
+					case r := <-c.resume:
+						// * 恢復任務，自目前時間重新計算
+						if timer != nil {
+							timer.Stop()
+						}
+						now = c.clock.Now().In(c.location)
+						if !r.task.enable {
+							break
+						}
+						r.task.mutex.Lock()
+						missed := r.task.pauseMissed
+						r.task.pauseMissed = false
+						r.task.next = r.task.nextRun(now)
+						r.task.mutex.Unlock()
+
+						if r.catchUp && missed {
+							r.task.mutex.Lock()
+							r.task.attempt = 1
+							r.task.mutex.Unlock()
+							c.run(r.task, nil)
+						}
+						c.reschedule(r.task)
 
-					case id := <-c.remove:
+					case entry := <-c.remove:
 						// * 移除任務觸發
 						if timer != nil {
 							timer.Stop()
 						}
-						now = time.Now().In(c.location)
-						for i, entry := range c.heap {
-							if entry.ID == id {
-								entry.enable = false
+						now = c.clock.Now().In(c.location)
+						// * 執行中已離開 heap 的任務同樣停用並取消
+						entry.enable = false
+						entry.cancel()
+						for i, e := range c.heap {
+							if e == entry {
 								heap.Remove(&c.heap, i)
 								break
 							}
 						}
 
-					case <-c.removeAll:
+					case entries := <-c.removeAll:
 						// * 移除任務觸發
 						if timer != nil {
 							timer.Stop()
 						}
-						now = time.Now().In(c.location)
+						now = c.clock.Now().In(c.location)
+						for _, entry := range entries {
+							entry.enable = false
+							entry.cancel()
+						}
 						// 完全清空 heap
 						for len(c.heap) > 0 {
-							heap.Pop(&c.heap)
+							heap.Pop(&c.heap).(*task).cancel()
 						}
 
 					case <-c.stop:
@@ -152,6 +313,8 @@ func (c *cron) Stop() context.Context {
 	if c.running {
 		c.stop <- struct{}{}
 		c.running = false
+		// * 通知執行中的任務取消
+		c.cancel()
 		c.depend.stop()
 	}
 
@@ -164,93 +327,281 @@ func (c *cron) Stop() context.Context {
 	return ctx
 }
 
-func (c *cron) run(e *task) {
-	e.mutex.RLock()
+// * 派送一次執行，依 OverlapPolicy 略過時回傳 false；done 接收本次結果
+func (c *cron) run(e *task, done chan TaskResult) bool {
+	e.mutex.Lock()
+	if e.running > 0 {
+		switch e.overlap {
+		case OverlapSkip:
+			e.skipped++
+			e.mutex.Unlock()
+			c.logger.Info(
+				"Task skipped, previous run still running",
+				"ID", int(e.ID),
+			)
+			return false
+		case OverlapQueue:
+			if e.queued {
+				e.skipped++
+				e.mutex.Unlock()
+				c.logger.Info(
+					"Task skipped, a run is already queued",
+					"ID", int(e.ID),
+				)
+				return false
+			}
+			e.queued = true
+			e.queuedDone = done
+			e.queuedRuns++
+			e.mutex.Unlock()
+			c.logger.Info(
+				"Task queued, previous run still running",
+				"ID", int(e.ID),
+			)
+			return true
+		case OverlapReplace:
+			if e.current != nil {
+				e.current.cancel()
+			}
+			c.logger.Info(
+				"Task replaced, previous run cancelled",
+				"ID", int(e.ID),
+			)
+		}
+	}
+	e.running++
 	hasDeps := len(e.after) > 0
-	e.mutex.RUnlock()
+	wait, waitState := e.wait, e.waitState
+	e.mutex.Unlock()
 
 	if hasDeps {
-		c.depend.addWait(e.ID, e.wait, e.waitState)
+		c.depend.addWait(e.ID, wait, waitState, done)
 	} else {
-		c.runAfter(e)
+		c.runAfter(e, done)
 	}
+	return true
 }
 
-func (c *cron) runAfter(e *task) {
+func (c *cron) runAfter(e *task, done chan TaskResult) {
 	c.wait.Add(1)
 	go func(entry *task) {
-		defer func() {
-			if r := recover(); r != nil {
-				// * 更新狀態至錯誤
-				entry.mutex.Lock()
-				entry.state = TaskFailed
-				entry.mutex.Unlock()
-
-				c.logger.Info(
-					"Recovered from panic",
-					"ID", int(entry.ID),
-					"error", r,
-				)
-			}
-		}()
 		defer c.wait.Done()
+		defer c.finish(entry)
 
 		// * 更新狀態至執行中
 		entry.mutex.Lock()
 		entry.state = TaskRunning
+		attempt, policy, timeout := entry.attempt, entry.retry, entry.delay
 		entry.mutex.Unlock()
 
+		start := c.clock.Now()
 		var taskError error
-		if entry.delay > 0 {
-			ctx, cancel := context.WithTimeout(context.Background(), entry.delay)
-			defer cancel()
-
-			done := make(chan struct{})
-			go func() {
-				defer cancel()
-
-				if err := entry.action(); err != nil {
-					taskError = err
-					c.logger.Error(
-						"Task failed",
-						"error", err,
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					taskError = fmt.Errorf("task panic: %v", r)
+					c.logger.Info(
+						"Recovered from panic",
+						"ID", int(entry.ID),
+						"error", r,
 					)
 				}
-				close(done)
 			}()
 
-			select {
-			case <-done:
-			case <-ctx.Done():
-				// * 任務超時
-				taskError = fmt.Errorf("task timeout: %d", entry.delay)
-				if entry.onDelay != nil {
-					entry.onDelay()
-				}
+			taskError = entry.execute(c.ctx, c.clock)
+		}()
+		end := c.clock.Now()
+
+		status := TaskCompleted
+		var delay time.Duration
+		var retry bool
+		if taskError != nil {
+			status = TaskFailed
+			if delay, retry = policy.backoff(attempt, taskError); retry {
+				status = TaskRetrying
+			}
+		}
+
+		if errors.Is(taskError, context.DeadlineExceeded) {
+			// * 任務超時
+			c.logger.Warn(
+				"Task timeout",
+				"ID", int(entry.ID),
+				"delay", timeout,
+				"attempt", attempt,
+			)
+		} else if taskError != nil {
+			c.logger.Error(
+				"Task failed",
+				"ID", int(entry.ID),
+				"attempt", attempt,
+				"error", taskError,
+			)
+		}
+
+		result := taskResult{
+			ID:       entry.ID,
+			status:   status,
+			start:    start,
+			end:      end,
+			duration: end.Sub(start),
+			error:    taskError,
+			attempt:  attempt,
+		}
+		c.depend.manager.update(result)
+		notify(done, result)
+
+		if retry {
+			c.scheduleRetry(entry, attempt, delay)
+		}
+	}(e)
+}
+
+// * 排入第 attempt+1 次執行，排程已停止時標記為失敗
+func (c *cron) scheduleRetry(e *task, attempt int, delay time.Duration) bool {
+	e.mutex.Lock()
+	e.attempt = attempt + 1
+	e.mutex.Unlock()
+
+	c.logger.Warn(
+		"Task retry scheduled",
+		"ID", int(e.ID),
+		"attempt", attempt+1,
+		"delay", delay,
+	)
+
+	select {
+	case c.retry <- taskRetry{task: e, at: c.clock.Now().Add(delay)}:
+		return true
+	case <-c.ctx.Done():
+		e.mutex.Lock()
+		e.state = TaskFailed
+		e.mutex.Unlock()
+		return false
+	}
+}
+
+// * 調整任務於 heap 的位置，不在 heap 內時加入
+func (c *cron) reschedule(e *task) {
+	index := -1
+	for i, entry := range c.heap {
+		if entry == e {
+			index = i
+			break
+		}
+	}
+
+	switch {
+	case index < 0 && !e.due().IsZero():
+		heap.Push(&c.heap, e)
+	case index >= 0 && e.due().IsZero():
+		heap.Remove(&c.heap, index)
+	case index >= 0:
+		heap.Fix(&c.heap, index)
+	}
+}
+
+// * 依錯過排程的處理方式執行到期任務
+func (c *cron) trigger(e *task, now time.Time) {
+	e.mutex.Lock()
+	misfire, endAt := e.misfire, e.endAt
+	e.mutex.Unlock()
+
+	late := now.Sub(e.next)
+	if misfire.Threshold <= 0 || late <= misfire.Threshold {
+		c.fire(e)
+		return
+	}
+
+	e.mutex.Lock()
+	e.missed++
+	e.mutex.Unlock()
+
+	c.logger.Warn(
+		"Task misfired",
+		"ID", int(e.ID),
+		"scheduled", e.next,
+		"late", late.String(),
+	)
+
+	switch misfire.Policy {
+	case MisfireSkip:
+		return
+	case MisfireFireAll:
+		limit := misfire.Limit
+		if limit <= 0 {
+			limit = defaultMisfireLimit
+		}
+		count := 0
+		for next := e.next; !next.IsZero() && !next.After(now); next = e.schedule.next(next) {
+			if !endAt.IsZero() && next.After(endAt) {
+				return
+			}
+			if count == limit {
 				c.logger.Warn(
-					"Task timeout",
-					"ID", int(entry.ID),
-					"delay", entry.delay,
+					"Task misfire limit reached",
+					"ID", int(e.ID),
+					"limit", limit,
 				)
+				return
 			}
-		} else {
-			if err := entry.action(); err != nil {
-				taskError = err
-				c.logger.Error(
-					"Task failed",
-					"error", err,
-				)
+			if !c.fire(e) {
+				return
 			}
+			count++
 		}
+	default:
+		c.fire(e)
+	}
+}
 
-		entry.mutex.Lock()
-		if taskError != nil {
-			// * 更新狀態至錯誤
-			entry.state = TaskFailed
-		} else {
-			// * 更新狀態至完成
-			entry.state = TaskCompleted
+// * 排程觸發的執行，達到 MaxRuns 時回傳 false
+func (c *cron) fire(e *task) bool {
+	e.mutex.Lock()
+	if e.maxRuns > 0 && e.fired >= e.maxRuns {
+		e.mutex.Unlock()
+		return false
+	}
+	e.fired++
+	e.mutex.Unlock()
+
+	c.run(e, nil)
+	return true
+}
+
+// * 任務結束，執行排隊中的下一次
+// * 單次任務保留結果一段時間後移除
+func (c *cron) expire(e *task) {
+	c.clock.AfterFunc(c.retention, func() {
+		c.Remove(e.ID)
+	})
+}
+
+func (c *cron) finish(e *task) {
+	e.mutex.Lock()
+	e.running--
+	queued := e.queued && e.running == 0
+	done := e.queuedDone
+	if queued {
+		e.queued = false
+		e.queuedDone = nil
+	}
+	expired := !queued && e.running == 0 && e.state != TaskRetrying && e.expired(c.clock.Now())
+	e.mutex.Unlock()
+
+	if expired {
+		c.expire(e)
+		return
+	}
+	if !queued {
+		return
+	}
+	// * 排程已停止時捨棄
+	if c.ctx.Err() != nil {
+		if done != nil {
+			close(done)
 		}
-		entry.mutex.Unlock()
-	}(e)
+		return
+	}
+	c.run(e, done)
 }
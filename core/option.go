@@ -0,0 +1,148 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// * 任務設定，傳入 Add 的選用參數
+type Option func(*task) error
+
+// * 任務名稱
+func WithName(name string) Option {
+	return func(t *task) error {
+		t.name = name
+		return nil
+	}
+}
+
+// * 任務描述
+func WithDescription(description string) Option {
+	return func(t *task) error {
+		t.description = description
+		return nil
+	}
+}
+
+// * 執行逾時
+func WithTimeout(timeout time.Duration) Option {
+	return func(t *task) error {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout must be positive, got %v", timeout)
+		}
+		t.delay = timeout
+		return nil
+	}
+}
+
+// * 逾時回呼
+func WithOnTimeout(fn func()) Option {
+	return func(t *task) error {
+		if fn == nil {
+			return fmt.Errorf("timeout callback is nil")
+		}
+		t.onDelay = fn
+		return nil
+	}
+}
+
+// * 前置依賴
+func WithDependencies(waits ...Wait) Option {
+	return func(t *task) error {
+		t.after = append(t.after, waits...)
+		return nil
+	}
+}
+
+// * 任務時區，表達式的 CRON_TZ= 前綴優先
+func WithLocation(location *time.Location) Option {
+	return func(t *task) error {
+		if location == nil {
+			return fmt.Errorf("location is nil")
+		}
+		if result, ok := t.schedule.(*scheduleResult); ok && result.location == nil {
+			result.location = location
+		}
+		return nil
+	}
+}
+
+// * 任務的夏令時間處理方式
+func WithDST(policy DSTPolicy) Option {
+	return func(t *task) error {
+		if result, ok := t.schedule.(*scheduleResult); ok {
+			result.dst = policy
+		}
+		return nil
+	}
+}
+
+// * 重複執行的處理方式
+func WithOverlap(policy OverlapPolicy) Option {
+	return func(t *task) error {
+		t.overlap = policy
+		return nil
+	}
+}
+
+// * 錯過排程的處理方式
+func WithMisfire(misfire Misfire) Option {
+	return func(t *task) error {
+		t.misfire = misfire
+		return nil
+	}
+}
+
+// * 失敗重試
+func WithRetry(retry Retry) Option {
+	return func(t *task) error {
+		t.retry = retry
+		return nil
+	}
+}
+
+// * 開始時間，之前不執行
+func WithStartAt(start time.Time) Option {
+	return func(t *task) error {
+		t.startAt = start
+		return nil
+	}
+}
+
+// * 結束時間，之後不再執行
+func WithEndAt(end time.Time) Option {
+	return func(t *task) error {
+		t.endAt = end
+		return nil
+	}
+}
+
+// * 排程執行次數上限
+func WithMaxRuns(n int) Option {
+	return func(t *task) error {
+		if n <= 0 {
+			return fmt.Errorf("max runs must be positive, got %d", n)
+		}
+		t.maxRuns = n
+		return nil
+	}
+}
+
+// * 每次執行的隨機延遲上限，需短於排程間隔
+func WithJitter(max time.Duration) Option {
+	return func(t *task) error {
+		if max <= 0 {
+			return fmt.Errorf("jitter must be positive, got %v", max)
+		}
+		t.jitter = max
+		return nil
+	}
+}
+
+// * 略過新增或更新時的依賴檢查，供互相參照的批次載入使用，載入後需呼叫 ValidateGraph
+func WithDeferredValidation() Option {
+	return func(t *task) error {
+		t.deferCheck = true
+		return nil
+	}
+}
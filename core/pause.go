@@ -0,0 +1,73 @@
+package core
+
+import (
+	"fmt"
+)
+
+// * 暫停任務，保留 ID、依賴與執行紀錄
+func (c *cron) Pause(id int64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, isExist := c.tasks[id]
+	if !isExist {
+		return fmt.Errorf("task not found: %d", id)
+	}
+
+	entry.mutex.Lock()
+	entry.paused = true
+	entry.mutex.Unlock()
+	return nil
+}
+
+// * 恢復任務，catchUp 時若暫停期間有錯過的排程則立即執行一次
+func (c *cron) Resume(id int64, catchUp bool) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, isExist := c.tasks[id]
+	if !isExist {
+		return fmt.Errorf("task not found: %d", id)
+	}
+	c.resumeTask(entry, catchUp)
+	return nil
+}
+
+// * 暫停所有任務，之後新增的任務同樣暫停，直到 ResumeAll
+func (c *cron) PauseAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.paused = true
+	for _, entry := range c.tasks {
+		entry.mutex.Lock()
+		entry.paused = true
+		entry.mutex.Unlock()
+	}
+}
+
+func (c *cron) ResumeAll(catchUp bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.paused = false
+	for _, entry := range c.tasks {
+		c.resumeTask(entry, catchUp)
+	}
+}
+
+func (c *cron) resumeTask(entry *task, catchUp bool) {
+	entry.mutex.Lock()
+	paused := entry.paused
+	entry.paused = false
+	entry.mutex.Unlock()
+
+	if !paused {
+		return
+	}
+
+	// * 未啟動時由 Start 重新計算下一次執行
+	if c.running {
+		c.resume <- taskResume{task: entry, catchUp: catchUp}
+	}
+}
@@ -8,29 +8,55 @@ func (c *cron) RemoveAll() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	entries := make([]*task, 0, len(c.tasks))
+	for _, entry := range c.tasks {
+		entries = append(entries, entry)
+	}
+	clear(c.tasks)
+	c.depend.manager.remove(entries)
+	for _, flow := range c.workflows {
+		c.depend.manager.remove(flow.steps)
+	}
+	clear(c.workflows)
+
 	if c.running {
-		c.removeAll <- struct{}{}
+		c.removeAll <- entries
 		return
 	}
 
-	for i := range c.heap {
-		c.heap[i].enable = false
+	for _, entry := range entries {
+		entry.enable = false
+		entry.cancel()
 	}
-	heap.Init(&c.heap)
+	c.heap = c.heap[:0]
 }
 
 func (c *cron) Remove(id int64) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	entry, isExist := c.tasks[id]
+	if !isExist {
+		return
+	}
+	delete(c.tasks, id)
+	// * 依賴此任務者隨即收到不存在的失敗，不需等到逾時
+	c.depend.manager.remove([]*task{entry})
+	if flow, isExist := c.workflows[id]; isExist {
+		c.depend.manager.remove(flow.steps)
+		delete(c.workflows, id)
+	}
+
+	// * 執行中的任務可能已離開 heap，一律停用並取消
 	if c.running {
-		c.remove <- id
+		c.remove <- entry
 		return
 	}
 
-	for i, entry := range c.heap {
-		if entry.ID == id {
-			entry.enable = false
+	entry.enable = false
+	entry.cancel()
+	for i, e := range c.heap {
+		if e == entry {
 			heap.Remove(&c.heap, i)
 			break
 		}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"fmt"
+)
+
+// * 立即執行一次，不影響原排程；回傳的 channel 於執行結束後收到結果並關閉
+func (c *cron) RunNow(id int64) (<-chan TaskResult, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.running {
+		return nil, fmt.Errorf("scheduler is not running")
+	}
+
+	entry, isExist := c.tasks[id]
+	if !isExist {
+		return nil, fmt.Errorf("task not found: %d", id)
+	}
+
+	entry.mutex.Lock()
+	entry.attempt = 1
+	entry.mutex.Unlock()
+
+	c.logger.Info(
+		"Task triggered manually",
+		"ID", int(id),
+	)
+
+	done := make(chan TaskResult, 1)
+	if !c.run(entry, done) {
+		return nil, fmt.Errorf("task skipped, previous run still running: %d", id)
+	}
+	return done, nil
+}
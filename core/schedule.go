@@ -2,103 +2,388 @@ package core
 
 import (
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-func (parser) parse(spec string) (schedule, error) {
+var (
+	monthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	dowNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+func (p parser) parse(spec string) (schedule, error) {
+	location, spec, err := parseLocation(strings.TrimSpace(spec))
+	if err != nil {
+		return nil, err
+	}
+	if spec == "" {
+		return nil, fmt.Errorf("empty spec")
+	}
+
+	var result schedule
 	if spec[0] == '@' {
-		return parseDescriptor(spec)
+		result, err = p.parseDescriptor(spec)
+	} else {
+		result, err = p.parseCron(spec)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return parseCron(spec)
+
+	// * 時區與夏令時間處理僅影響 cron 表達式
+	if r, ok := result.(*scheduleResult); ok {
+		r.dst = p.dst
+		if location != nil {
+			r.location = location
+		}
+	}
+	return result, nil
+}
+
+// * 解析 CRON_TZ= 或 TZ= 前綴
+func parseLocation(spec string) (*time.Location, string, error) {
+	if !strings.HasPrefix(spec, "CRON_TZ=") && !strings.HasPrefix(spec, "TZ=") {
+		return nil, spec, nil
+	}
+
+	prefix, rest, _ := strings.Cut(spec, " ")
+	_, name, _ := strings.Cut(prefix, "=")
+	// ! time.LoadLocation("") 會回傳 UTC
+	if name == "" {
+		return nil, "", fmt.Errorf("empty timezone in %s", prefix)
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid timezone %s: %v", name, err)
+	}
+	return location, strings.TrimSpace(rest), nil
 }
 
 func (r delayScheduleResult) next(t time.Time) time.Time {
 	return t.Add(r.delay)
 }
 
+// * 指定時間之前回傳該時間，之後不再觸發
+func (r atScheduleResult) next(t time.Time) time.Time {
+	if t.Before(r.at) {
+		return r.at.In(t.Location())
+	}
+	return time.Time{}
+}
+
+// * 最遠搜尋年數，超過即視為不會再觸發
+const maxSearchYears = 50
+
+// * 依欄位跳躍計算：月 → 日 → 時 → 分 → 秒
 func (s *scheduleResult) next(t time.Time) time.Time {
-	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second)
+	if s.location != nil {
+		t = t.In(s.location)
+	}
+
+	result := s.nextFrom(t, wallClock(t).Add(time.Second))
+
+	// * 夏令時間結束前，重複時段的第二次出現其牆上時間早於 t
+	if s.wallTime() {
+		_, end := t.ZoneBounds()
+		if !end.IsZero() && !wallClock(end).After(wallClock(t)) {
+			if repeated := s.nextFrom(t, wallClock(end)); !repeated.IsZero() && (result.IsZero() || repeated.Before(result)) {
+				result = repeated
+			}
+		}
+	}
+	return result
+}
+
+// * 自牆上時間 w 起，找出第一個晚於 t 的觸發時間
+func (s *scheduleResult) nextFrom(t, w time.Time) time.Time {
+	location := t.Location()
+	limit := w.AddDate(maxSearchYears, 0, 0)
+
+	for w.Before(limit) {
+		if month, ok := s.month.nextValue(int(w.Month())); !ok {
+			w = time.Date(w.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		} else if month != int(w.Month()) {
+			w = time.Date(w.Year(), time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		}
+
+		if !s.matchDay(w) {
+			w = time.Date(w.Year(), w.Month(), w.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+
+		if hour, ok := s.hour.nextValue(w.Hour()); !ok {
+			w = time.Date(w.Year(), w.Month(), w.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		} else if hour != w.Hour() {
+			w = time.Date(w.Year(), w.Month(), w.Day(), hour, 0, 0, 0, time.UTC)
+		}
 
-	for {
-		if s.matchTime(t) {
-			return t
+		if minute, ok := s.minute.nextValue(w.Minute()); !ok {
+			w = time.Date(w.Year(), w.Month(), w.Day(), w.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		} else if minute != w.Minute() {
+			w = time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), minute, 0, 0, time.UTC)
 		}
-		t = t.Add(time.Minute)
+
+		if second, ok := s.second.nextValue(w.Second()); !ok {
+			w = time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute()+1, 0, 0, time.UTC)
+			continue
+		} else if second != w.Second() {
+			w = time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), second, 0, time.UTC)
+		}
+
+		for _, result := range s.resolve(w, location) {
+			if result.After(t) {
+				return result
+			}
+		}
+		w = w.Add(time.Second)
 	}
+
+	return time.Time{}
+}
+
+// * 牆上時間對應的實際時間：
+// * 跳過的時段（夏令時間開始）DSTOnce 於時段結束時執行一次，DSTWallClock 不執行；
+// * 重複的時段（夏令時間結束）DSTOnce 僅取第一次，DSTWallClock 兩次皆執行
+func (s *scheduleResult) resolve(w time.Time, location *time.Location) []time.Time {
+	var result []time.Time
+	minOffset := 0
+	for i, probe := range []time.Time{w.Add(-24 * time.Hour), w, w.Add(24 * time.Hour)} {
+		_, offset := probe.In(location).Zone()
+		if i == 0 || offset < minOffset {
+			minOffset = offset
+		}
+
+		instant := w.Add(-time.Duration(offset) * time.Second)
+		if _, actual := instant.In(location).Zone(); actual != offset {
+			continue
+		}
+		if containsTime(result, instant) {
+			continue
+		}
+		result = append(result, instant.In(location))
+	}
+
+	if len(result) == 0 {
+		if s.wallTime() {
+			return nil
+		}
+		// * 以較小偏移換算的時間落於新時區內，其起點即為跳躍結束時間
+		start, _ := w.Add(-time.Duration(minOffset) * time.Second).In(location).ZoneBounds()
+		return []time.Time{start.In(location)}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Before(result[j])
+	})
+	if !s.wallTime() {
+		return result[:1]
+	}
+	return result
+}
+
+// * 依牆上時間執行：DSTWallClock，或時、分、秒含 * 的頻繁任務（與 Vixie cron 相同，僅固定時間的任務調整）
+func (s *scheduleResult) wallTime() bool {
+	return s.dst == DSTWallClock ||
+		s.second.unrestricted() || s.minute.unrestricted() || s.hour.unrestricted()
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, e := range times {
+		if e.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// * 以 UTC 表示的牆上時間
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
 }
 
 func (s *scheduleResult) matchTime(t time.Time) bool {
-	return s.matchField(s.minute, t.Minute()) &&
+	return s.matchField(s.second, t.Second()) &&
+		s.matchField(s.minute, t.Minute()) &&
 		s.matchField(s.hour, t.Hour()) &&
-		s.matchField(s.dom, t.Day()) &&
 		s.matchField(s.month, int(t.Month())) &&
-		s.matchField(s.dow, int(t.Weekday()))
+		s.matchDay(t)
 }
 
-func (s *scheduleResult) matchField(field scheduleField, value int) bool {
-	if field.All {
+// * 日與週皆有限制時預設取聯集（POSIX），dayAnd 時取交集
+func (s *scheduleResult) matchDay(t time.Time) bool {
+	if !s.dayAnd && !s.dom.unrestricted() && !s.dow.unrestricted() {
+		return s.matchDom(t) || s.matchDow(t)
+	}
+	return s.matchDom(t) && s.matchDow(t)
+}
+
+func (s *scheduleResult) matchDom(t time.Time) bool {
+	field := s.dom
+	if !field.special() {
+		return s.matchField(field, t.Day())
+	}
+
+	day := t.Day()
+	last := daysIn(t.Year(), t.Month())
+
+	if field.Last && day == last {
+		return true
+	}
+	if field.LastWeekday && day == nearestWeekday(t.Year(), t.Month(), last) {
 		return true
 	}
+	for _, n := range field.Weekday {
+		if day == nearestWeekday(t.Year(), t.Month(), n) {
+			return true
+		}
+	}
+	return containsValue(field.Values, day)
+}
 
-	if field.Step > 0 {
-		return value%field.Step == 0
+func (s *scheduleResult) matchDow(t time.Time) bool {
+	field := s.dow
+	if !field.special() {
+		return s.matchField(field, int(t.Weekday()))
 	}
 
-	if len(field.Values) > 0 {
-		for _, v := range field.Values {
-			if v == value {
-				return true
-			}
+	dow := int(t.Weekday())
+	day := t.Day()
+	last := daysIn(t.Year(), t.Month())
+
+	for _, d := range field.LastDow {
+		if dow == d && day+7 > last {
+			return true
 		}
-		return false
 	}
+	for _, e := range field.Nth {
+		if dow == e.dow && (day-1)/7+1 == e.n {
+			return true
+		}
+	}
+	return containsValue(field.Values, dow)
+}
 
-	return field.Value == value
+func (f scheduleField) special() bool {
+	return f.Last ||
+		f.LastWeekday ||
+		len(f.Weekday) > 0 ||
+		len(f.LastDow) > 0 ||
+		len(f.Nth) > 0
+}
+
+// * 大於等於 value 的最小值
+func (f scheduleField) nextValue(value int) (int, bool) {
+	if f.All {
+		return value, true
+	}
+	i := sort.SearchInts(f.Values, value)
+	if i == len(f.Values) {
+		return 0, false
+	}
+	return f.Values[i], true
+}
+
+func containsValue(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
-func parseDescriptor(spec string) (schedule, error) {
+// * 該月天數（含閏年）
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// * 最接近 n 日的平日，不跨月；n 超過該月天數時回傳 0
+func nearestWeekday(year int, month time.Month, n int) int {
+	last := daysIn(year, month)
+	if n > last {
+		return 0
+	}
+
+	switch time.Date(year, month, n, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if n == 1 {
+			return n + 2
+		}
+		return n - 1
+	case time.Sunday:
+		if n == last {
+			return n - 2
+		}
+		return n + 1
+	}
+	return n
+}
+
+func (s *scheduleResult) matchField(field scheduleField, value int) bool {
+	if field.All {
+		return true
+	}
+
+	return containsValue(field.Values, value)
+}
+
+func (p parser) parseDescriptor(spec string) (schedule, error) {
 	switch spec {
 	case "@yearly", "@annually":
 		return &scheduleResult{
-			scheduleField{Value: 0},
-			scheduleField{Value: 0},
-			scheduleField{Value: 1},
-			scheduleField{Value: 1},
-			scheduleField{All: true},
+			second: scheduleField{Values: []int{0}},
+			minute: scheduleField{Values: []int{0}},
+			hour:   scheduleField{Values: []int{0}},
+			dom:    scheduleField{Values: []int{1}},
+			month:  scheduleField{Values: []int{1}},
+			dow:    scheduleField{All: true},
 		}, nil
 	case "@monthly":
 		return &scheduleResult{
-			scheduleField{Value: 0},
-			scheduleField{Value: 0},
-			scheduleField{Value: 1},
-			scheduleField{All: true},
-			scheduleField{All: true},
+			second: scheduleField{Values: []int{0}},
+			minute: scheduleField{Values: []int{0}},
+			hour:   scheduleField{Values: []int{0}},
+			dom:    scheduleField{Values: []int{1}},
+			month:  scheduleField{All: true},
+			dow:    scheduleField{All: true},
 		}, nil
 	case "@weekly":
 		return &scheduleResult{
-			scheduleField{Value: 0},
-			scheduleField{Value: 0},
-			scheduleField{All: true},
-			scheduleField{All: true},
-			scheduleField{Value: 0},
+			second: scheduleField{Values: []int{0}},
+			minute: scheduleField{Values: []int{0}},
+			hour:   scheduleField{Values: []int{0}},
+			dom:    scheduleField{All: true},
+			month:  scheduleField{All: true},
+			dow:    scheduleField{Values: []int{0}},
 		}, nil
 	case "@daily", "@midnight":
 		return &scheduleResult{
-			scheduleField{Value: 0},
-			scheduleField{Value: 0},
-			scheduleField{All: true},
-			scheduleField{All: true},
-			scheduleField{All: true},
+			second: scheduleField{Values: []int{0}},
+			minute: scheduleField{Values: []int{0}},
+			hour:   scheduleField{Values: []int{0}},
+			dom:    scheduleField{All: true},
+			month:  scheduleField{All: true},
+			dow:    scheduleField{All: true},
 		}, nil
 	case "@hourly":
 		return &scheduleResult{
-			scheduleField{Value: 0},
-			scheduleField{All: true},
-			scheduleField{All: true},
-			scheduleField{All: true},
-			scheduleField{All: true},
+			second: scheduleField{Values: []int{0}},
+			minute: scheduleField{Values: []int{0}},
+			hour:   scheduleField{All: true},
+			dom:    scheduleField{All: true},
+			month:  scheduleField{All: true},
+			dow:    scheduleField{All: true},
 		}, nil
 	}
 
@@ -107,23 +392,64 @@ func parseDescriptor(spec string) (schedule, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse @every: %v", err)
 		}
-		if duration < 30*time.Second {
-			return nil, fmt.Errorf("@every minimum interval is 30s, got %v", duration)
+		minimum := 30 * time.Second
+		if p.seconds {
+			minimum = time.Second
+		}
+		if duration < minimum {
+			return nil, fmt.Errorf("@every minimum interval is %v, got %v", minimum, duration)
 		}
 		return delayScheduleResult{duration}, nil
 	}
 
+	// * 單次執行，時間需為 RFC 3339 格式
+	if strings.HasPrefix(spec, "@at ") {
+		at, err := time.Parse(time.RFC3339, strings.TrimSpace(spec[4:]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse @at: %v", err)
+		}
+		return atScheduleResult{at}, nil
+	}
+
 	return nil, fmt.Errorf("failed to parse: %s", spec)
 }
 
-func parseCron(spec string) (schedule, error) {
+func (p parser) parseCron(spec string) (schedule, error) {
 	fields := strings.Fields(spec)
-	if len(fields) != 5 {
+
+	schedule := &scheduleResult{
+		second: scheduleField{Values: []int{0}},
+		dayAnd: p.dayAnd,
+	}
+	var err error
+
+	switch {
+	case len(fields) == 5:
+		fields = append([]string{"0"}, fields...)
+	// * 開頭為秒欄位
+	case len(fields) == 6 && p.seconds:
+	case p.seconds:
+		return nil, fmt.Errorf("requires 5 or 6 values, got %d", len(fields))
+	default:
 		return nil, fmt.Errorf("requires 5 values, got %d", len(fields))
 	}
 
-	schedule := &scheduleResult{}
-	var err error
+	// * H 依序對應秒、分、時、日、月、週，日僅取 1-28 以確保每月皆存在
+	fields[5] = replaceNames(fields[5], dowNames)
+	for i, bound := range [][2]int{{0, 59}, {0, 59}, {0, 23}, {1, 28}, {1, 12}, {0, 6}} {
+		if !strings.Contains(fields[i], "H") {
+			continue
+		}
+		if fields[i], err = p.hashField(fields[i], i, bound[0], bound[1]); err != nil {
+			return nil, err
+		}
+		schedule.hashed = true
+	}
+
+	if schedule.second, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	fields = fields[1:]
 
 	if schedule.minute, err = parseField(fields[0], 0, 59); err != nil {
 		return nil, err
@@ -131,40 +457,271 @@ func parseCron(spec string) (schedule, error) {
 	if schedule.hour, err = parseField(fields[1], 0, 23); err != nil {
 		return nil, err
 	}
-	if schedule.dom, err = parseField(fields[2], 1, 31); err != nil {
+	if schedule.dom, err = parseDom(fields[2]); err != nil {
 		return nil, err
 	}
-	if schedule.month, err = parseField(fields[3], 1, 12); err != nil {
+	if schedule.month, err = parseField(replaceNames(fields[3], monthNames), 1, 12); err != nil {
 		return nil, err
 	}
-	if schedule.dow, err = parseField(fields[4], 0, 6); err != nil {
+	if schedule.dow, err = parseDow(fields[4]); err != nil {
 		return nil, err
 	}
+	schedule.dom.Star, schedule.dow.Star = isStar(fields[2]), isStar(fields[4])
+
+	now := time.Now()
+	if p.clock != nil {
+		now = p.clock.Now()
+	}
+	if schedule.next(now).IsZero() {
+		return nil, fmt.Errorf("%s never fires within %d years", spec, maxSearchYears)
+	}
 
 	return schedule, nil
 }
 
-func parseField(field string, min, max int) (scheduleField, error) {
-	if field == "*" {
+// * 將 H、H(a-b)、H/n、H(a-b)/n 換為由任務名稱雜湊而得的固定值
+func (p parser) hashField(field string, index, min, max int) (string, error) {
+	parts := strings.Split(field, ",")
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "H") {
+			if strings.Contains(part, "H") {
+				return "", fmt.Errorf("invalid hash format: %s", part)
+			}
+			continue
+		}
+
+		start, end := min, max
+		rest := part[1:]
+		if strings.HasPrefix(rest, "(") {
+			body, after, ok := strings.Cut(rest[1:], ")")
+			if !ok {
+				return "", fmt.Errorf("invalid hash format: %s", part)
+			}
+			rangeField, err := parseRange(body, min, max)
+			if err != nil {
+				return "", err
+			}
+			start = rangeField.Values[0]
+			end = rangeField.Values[len(rangeField.Values)-1]
+			rest = after
+		}
+
+		seed := int(hashKey(p.hash, byte(index), byte(i)) % uint64(end-start+1))
+		switch {
+		case rest == "":
+			parts[i] = strconv.Itoa(start + seed)
+		case strings.HasPrefix(rest, "/"):
+			step, err := strconv.Atoi(rest[1:])
+			if err != nil {
+				return "", fmt.Errorf("invalid step value: %v", err)
+			}
+			if step <= 0 {
+				return "", fmt.Errorf("step must greater than 0, got %d", step)
+			}
+			// * 起點落於第一個步進區間內
+			parts[i] = fmt.Sprintf("%d-%d/%d", start+seed%step, end, step)
+		default:
+			return "", fmt.Errorf("invalid hash format: %s", part)
+		}
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// * FNV-1a 雜湊，供 H 欄位與隨機延遲取得固定種子
+func hashKey(key string, salt ...byte) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write(salt)
+	return h.Sum64()
+}
+
+// * 以 * 或 ? 開頭的欄位與 Vixie cron 的 DOM_STAR/DOW_STAR 相同，不視為限制
+func isStar(field string) bool {
+	return strings.HasPrefix(field, "*") || strings.HasPrefix(field, "?")
+}
+
+func (f scheduleField) unrestricted() bool {
+	return f.All || f.Star
+}
+
+// * 日欄位：支援 ?、L、LW、nW
+func parseDom(field string) (scheduleField, error) {
+	if field == "?" {
 		return scheduleField{All: true}, nil
 	}
+	if !strings.ContainsAny(field, "LW") {
+		return parseField(field, 1, 31)
+	}
+
+	var result scheduleField
+	for _, part := range strings.Split(field, ",") {
+		switch {
+		case part == "L":
+			result.Last = true
+		case part == "LW":
+			result.LastWeekday = true
+		case strings.HasSuffix(part, "W"):
+			day, err := strconv.Atoi(part[:len(part)-1])
+			if err != nil {
+				return scheduleField{}, fmt.Errorf("invalid weekday value: %s", part)
+			}
+			if day < 1 || day > 31 {
+				return scheduleField{}, fmt.Errorf("%d out of range [1, 31]", day)
+			}
+			result.Weekday = append(result.Weekday, day)
+		default:
+			values, err := parsePlainValues(part, 1, 31)
+			if err != nil {
+				return scheduleField{}, err
+			}
+			result.Values = append(result.Values, values...)
+		}
+	}
+	sort.Ints(result.Values)
+	return result, nil
+}
 
-	if strings.HasPrefix(field, "*/") {
-		str := field[2:]
-		step, err := strconv.Atoi(str)
+// * 週欄位：支援 ?、nL、n#k，7 視為週日
+func parseDow(field string) (scheduleField, error) {
+	field = replaceNames(field, dowNames)
+	if field == "?" {
+		return scheduleField{All: true}, nil
+	}
+	if !strings.ContainsAny(field, "L#") {
+		result, err := parseField(field, 0, 7)
 		if err != nil {
-			return scheduleField{}, fmt.Errorf("invalid step value: %v", err)
+			return scheduleField{}, err
 		}
-		if step <= 0 {
-			return scheduleField{}, fmt.Errorf("step must greater than 0, got %d", step)
+		return normalizeSunday(result), nil
+	}
+
+	var result scheduleField
+	for _, part := range strings.Split(field, ",") {
+		switch {
+		case strings.HasSuffix(part, "L"):
+			dow, err := strconv.Atoi(part[:len(part)-1])
+			if err != nil {
+				return scheduleField{}, fmt.Errorf("invalid last weekday value: %s", part)
+			}
+			if dow < 0 || dow > 7 {
+				return scheduleField{}, fmt.Errorf("%d out of range [0, 7]", dow)
+			}
+			result.LastDow = append(result.LastDow, dow)
+		case strings.Contains(part, "#"):
+			pair := strings.SplitN(part, "#", 2)
+			dow, err := strconv.Atoi(pair[0])
+			if err != nil {
+				return scheduleField{}, fmt.Errorf("invalid weekday value: %s", part)
+			}
+			n, err := strconv.Atoi(pair[1])
+			if err != nil {
+				return scheduleField{}, fmt.Errorf("invalid nth value: %s", part)
+			}
+			if dow < 0 || dow > 7 {
+				return scheduleField{}, fmt.Errorf("%d out of range [0, 7]", dow)
+			}
+			if n < 1 || n > 5 {
+				return scheduleField{}, fmt.Errorf("%d out of range [1, 5]", n)
+			}
+			result.Nth = append(result.Nth, nthWeekday{dow: dow, n: n})
+		default:
+			values, err := parsePlainValues(part, 0, 7)
+			if err != nil {
+				return scheduleField{}, err
+			}
+			result.Values = append(result.Values, values...)
 		}
-		return scheduleField{Step: step}, nil
+	}
+	return normalizeSunday(result), nil
+}
+
+// * 將週日的 7 轉為 0
+func normalizeSunday(field scheduleField) scheduleField {
+	sunday := func(v int) int {
+		if v == 7 {
+			return 0
+		}
+		return v
+	}
+
+	if len(field.Values) > 0 {
+		var values []int
+		for _, v := range field.Values {
+			if v = sunday(v); !containsValue(values, v) {
+				values = append(values, v)
+			}
+		}
+		sort.Ints(values)
+		field.Values = values
+	}
+	for i := range field.LastDow {
+		field.LastDow[i] = sunday(field.LastDow[i])
+	}
+	for i := range field.Nth {
+		field.Nth[i].dow = sunday(field.Nth[i].dow)
+	}
+	return field
+}
+
+// * 將月份、星期英文縮寫（不分大小寫）轉為數值
+func replaceNames(field string, names map[string]int) string {
+	var builder strings.Builder
+	start := -1
+
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := field[start:end]
+		if value, ok := names[strings.ToLower(word)]; ok {
+			builder.WriteString(strconv.Itoa(value))
+		} else {
+			builder.WriteString(word)
+		}
+		start = -1
+	}
+
+	for i := 0; i < len(field); i++ {
+		ch := field[i]
+		if (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+		builder.WriteByte(ch)
+	}
+	flush(len(field))
+
+	return builder.String()
+}
+
+// * 與特殊字元混用的單值或範圍
+func parsePlainValues(part string, min, max int) ([]int, error) {
+	field, err := parseField(part, min, max)
+	if err != nil {
+		return nil, err
+	}
+	if field.All {
+		return nil, fmt.Errorf("cannot combine %s with special characters", part)
+	}
+	return field.Values, nil
+}
+
+func parseField(field string, min, max int) (scheduleField, error) {
+	if field == "*" {
+		return scheduleField{All: true}, nil
 	}
 
 	if strings.Contains(field, ",") {
 		return parseList(field, min, max)
 	}
 
+	if strings.Contains(field, "/") {
+		return parseStep(field, min, max)
+	}
+
 	if strings.Contains(field, "-") {
 		return parseRange(field, min, max)
 	}
@@ -178,7 +735,50 @@ func parseField(field string, min, max int) (scheduleField, error) {
 		return scheduleField{}, fmt.Errorf("%d out of range [%d, %d]", value, min, max)
 	}
 
-	return scheduleField{Value: value}, nil
+	return scheduleField{Values: []int{value}}, nil
+}
+
+// * 步進展開為實際值：*/n、a-b/n、n/m
+func parseStep(field string, min, max int) (scheduleField, error) {
+	parts := strings.Split(field, "/")
+	if len(parts) != 2 || parts[0] == "" {
+		return scheduleField{}, fmt.Errorf("invalid step format: %s", field)
+	}
+
+	step, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return scheduleField{}, fmt.Errorf("invalid step value: %v", err)
+	}
+	if step <= 0 {
+		return scheduleField{}, fmt.Errorf("step must greater than 0, got %d", step)
+	}
+
+	start, end := min, max
+	switch base := parts[0]; {
+	case base == "*":
+	case strings.Contains(base, "-"):
+		rangeField, err := parseRange(base, min, max)
+		if err != nil {
+			return scheduleField{}, err
+		}
+		start = rangeField.Values[0]
+		end = rangeField.Values[len(rangeField.Values)-1]
+	default:
+		// * n/m 由 n 起算至欄位上限
+		if start, err = strconv.Atoi(base); err != nil {
+			return scheduleField{}, fmt.Errorf("invalid start: %v", err)
+		}
+		if start < min || start > max {
+			return scheduleField{}, fmt.Errorf("%d out of range [%d, %d]", start, min, max)
+		}
+	}
+
+	var values []int
+	for i := start; i <= end; i += step {
+		values = append(values, i)
+	}
+
+	return scheduleField{Values: values}, nil
 }
 
 func parseRange(field string, min, max int) (scheduleField, error) {
@@ -246,26 +846,15 @@ func parseList(field string, min, max int) (scheduleField, error) {
 			return scheduleField{}, fmt.Errorf("empty field %s", field)
 		}
 
-		var values []int
-
-		if strings.Contains(part, "-") {
-			rangeField, err := parseRange(part, min, max)
-			if err != nil {
-				return scheduleField{}, fmt.Errorf("invalid range %v", err)
-			}
-			values = rangeField.Values
-		} else {
-			value, err := strconv.Atoi(part)
-			if err != nil {
-				return scheduleField{}, fmt.Errorf("invalid value %v", err)
-			}
-			if value < min || value > max {
-				return scheduleField{}, fmt.Errorf("%d out of range [%d, %d]", value, min, max)
-			}
-			values = []int{value}
+		partField, err := parseField(part, min, max)
+		if err != nil {
+			return scheduleField{}, fmt.Errorf("invalid list item %v", err)
+		}
+		if partField.All {
+			return scheduleField{}, fmt.Errorf("cannot use * in list %s", field)
 		}
 
-		for _, v := range values {
+		for _, v := range partField.Values {
 			if !valueSet[v] {
 				valueSet[v] = true
 				allValues = append(allValues, v)
@@ -276,6 +865,7 @@ func parseList(field string, min, max int) (scheduleField, error) {
 	if len(allValues) == 0 {
 		return scheduleField{}, fmt.Errorf("empty list field: %s", field)
 	}
+	sort.Ints(allValues)
 
 	return scheduleField{Values: allValues}, nil
 }
@@ -1,24 +1,77 @@
 package core
 
-func (c *cron) List() []*task {
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+)
+
+// * 依 ID 排序回傳所有任務的快照
+func (c *cron) List() []TaskInfo {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	tasks := make([]*task, 0, len(c.heap))
-	for _, t := range c.heap {
-		if t.enable {
-			tasks = append(tasks, t)
-		}
+	tasks := make([]TaskInfo, 0, len(c.tasks))
+	for _, t := range c.tasks {
+		tasks = append(tasks, t.info())
 	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
 	return tasks
 }
 
+func (c *cron) Get(id int64) (TaskInfo, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	t, isExist := c.tasks[id]
+	if !isExist {
+		return TaskInfo{}, false
+	}
+	return t.info(), true
+}
+
+func (t *task) info() TaskInfo {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	info := TaskInfo{
+		ID:          t.ID,
+		Name:        t.name,
+		Description: t.description,
+		Spec:        t.spec,
+		Next:        t.next,
+		Prev:        t.prev,
+		State:       t.state,
+		Paused:      t.paused,
+		Runs:        t.runs,
+		Failures:    t.failures,
+		Skipped:     t.skipped,
+		Queued:      t.queuedRuns,
+		Missed:      t.missed,
+	}
+	if t.result != nil {
+		result := t.result.export()
+		info.LastResult = &result
+	}
+	if len(t.after) > 0 {
+		info.Dependencies = make([]Wait, len(t.after))
+		copy(info.Dependencies, t.after)
+	}
+	return info
+}
+
 func (h taskHeap) Len() int {
 	return len(h)
 }
 
 func (h taskHeap) Less(i, j int) bool {
-	return h[i].next.Before(h[j].next)
+	return h[i].due().Before(h[j].due())
 }
 
 func (h taskHeap) Swap(i, j int) {
@@ -36,3 +89,172 @@ func (h *taskHeap) Pop() any {
 	*h = old[0 : n-1]
 	return item
 }
+
+// * 執行任務本體，context 於逾時、移除任務或停止排程時取消
+func (t *task) execute(parent context.Context, clock Clock) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	run := &taskRun{cancel: cancel}
+	t.mutex.Lock()
+	t.current = run
+	delay, onDelay := t.delay, t.onDelay
+	t.mutex.Unlock()
+	defer func() {
+		t.mutex.Lock()
+		if t.current == run {
+			t.current = nil
+		}
+		t.mutex.Unlock()
+	}()
+
+	// * 任務被移除時一併取消
+	stop := context.AfterFunc(t.ctx, cancel)
+	defer stop()
+
+	if delay <= 0 {
+		return t.action(ctx)
+	}
+
+	ctx, cancelTimeout := withTimeout(clock, ctx, delay)
+	defer cancelTimeout()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("task panic: %v", r)
+			}
+		}()
+		done <- t.action(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if !errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+			return ctx.Err()
+		}
+		// * 任務超時
+		if onDelay != nil {
+			onDelay()
+		}
+		return fmt.Errorf("task timeout: %v: %w", delay, context.DeadlineExceeded)
+	}
+}
+
+// * 依執行區間與次數上限計算下一次執行，需持有 mutex
+func (t *task) nextRun(now time.Time) time.Time {
+	if t.maxRuns > 0 && t.fired >= t.maxRuns {
+		return time.Time{}
+	}
+
+	from := now
+	// * 隨機延遲內觸發時，自原排程時間起算，避免延遲累積
+	if t.jitter > 0 && !t.base.IsZero() && !t.base.After(now) && now.Sub(t.base) <= t.jitter {
+		from = t.base
+	}
+	if t.startAt.After(from) {
+		from = t.startAt
+		// * 包含開始時間本身；固定間隔自開始時間起算
+		if _, ok := t.schedule.(delayScheduleResult); !ok {
+			from = from.Add(-time.Nanosecond)
+		}
+	}
+
+	next := t.schedule.next(from)
+	t.base = next
+	if t.jitter > 0 && !next.IsZero() {
+		next = next.Add(time.Duration(t.random.Int64N(int64(t.jitter))))
+	}
+	if !t.endAt.IsZero() && next.After(t.endAt) {
+		return time.Time{}
+	}
+	return next
+}
+
+// * 結束時間需晚於開始時間
+func (t *task) validateWindow() error {
+	if !t.startAt.IsZero() && !t.endAt.IsZero() && !t.endAt.After(t.startAt) {
+		return fmt.Errorf("end time %v must be after start time %v", t.endAt, t.startAt)
+	}
+	return nil
+}
+
+// * 是否為有結束條件的任務，用於區分正常結束與無法觸發
+func (t *task) bounded() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	_, once := t.schedule.(atScheduleResult)
+	return once || !t.endAt.IsZero() || t.maxRuns > 0
+}
+
+// * 單次任務已執行完畢，需持有 mutex
+func (t *task) expired(now time.Time) bool {
+	_, ok := t.schedule.(atScheduleResult)
+	return ok && t.schedule.next(now).IsZero()
+}
+
+// * 下一次觸發時間，待重試時取較早者
+func (t *task) due() time.Time {
+	if !t.retryAt.IsZero() && (t.next.IsZero() || t.retryAt.Before(t.next)) {
+		return t.retryAt
+	}
+	return t.next
+}
+
+// * 第 attempt 次執行失敗後的重試等待時間，不重試時回傳 false
+func (r Retry) backoff(attempt int, err error) (time.Duration, bool) {
+	if attempt >= r.MaxAttempts {
+		return 0, false
+	}
+	if r.Retryable != nil && !r.Retryable(err) {
+		return 0, false
+	}
+
+	delay := r.Delay
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+	multiplier := r.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	wait := float64(delay) * math.Pow(multiplier, float64(attempt-1))
+	if r.MaxDelay > 0 && wait > float64(r.MaxDelay) {
+		wait = float64(r.MaxDelay)
+	}
+	if r.Jitter > 0 {
+		wait += wait * r.Jitter * (rand.Float64()*2 - 1)
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > math.MaxInt64 {
+		wait = math.MaxInt64
+	}
+	return time.Duration(wait), true
+}
+
+func (r taskResult) export() TaskResult {
+	return TaskResult{
+		Status:   r.status,
+		Start:    r.start,
+		End:      r.end,
+		Duration: r.duration,
+		Error:    r.error,
+		Attempt:  r.attempt,
+	}
+}
+
+// * 將結果送至 RunNow 的等待者
+func notify(done chan TaskResult, r taskResult) {
+	if done == nil {
+		return
+	}
+	done <- r.export()
+	close(done)
+}
@@ -1,7 +1,9 @@
 package core
 
 import (
+	"context"
 	"log/slog"
+	"math/rand/v2"
 	"sync"
 	"time"
 )
@@ -16,36 +18,184 @@ const (
 	TaskRunning
 	TaskCompleted
 	TaskFailed
+	// * 執行失敗，等待重試
+	TaskRetrying
 )
 
 type Config struct {
 	Location *time.Location
+	// * 啟用秒欄位（6 欄位 cron 表達式）
+	Seconds bool
+	// * 日與週欄位的比對方式，預設 DayOr
+	DayMatch DayMatch
+	// * 夏令時間轉換的處理方式，預設 DSTOnce
+	DST DSTPolicy
+	// * 時間來源，nil 時使用系統時間；測試可替換為 FakeClock
+	Clock Clock
+	// * 錯過排程的處理方式，可於 Add 逐任務覆寫
+	Misfire Misfire
+	// * 單次任務執行完畢後保留於 List/Get 的時間，0 時為 defaultRetention
+	Retention time.Duration
 }
 
+const defaultRetention = time.Hour
+
+// * 錯過排程的判定與處理
+type Misfire struct {
+	// * 處理方式，預設 MisfireFireOnce
+	Policy MisfirePolicy
+	// * 延遲超過此值視為錯過，0 為停用
+	Threshold time.Duration
+	// * MisfireFireAll 單次補執行的上限，0 時為 defaultMisfireLimit
+	Limit int
+}
+
+type MisfirePolicy int
+
+const (
+	// * 立即執行一次
+	MisfireFireOnce MisfirePolicy = iota
+	// * 補執行每一次錯過的排程，最多 Limit 次
+	MisfireFireAll
+	// * 略過，等待下一次排程
+	MisfireSkip
+)
+
+const defaultMisfireLimit = 10
+
+// * 失敗重試，以指數退避排入 heap
+type Retry struct {
+	// * 含首次執行的總次數，1 以下不重試
+	MaxAttempts int
+	// * 首次重試的等待時間，0 時為 defaultRetryDelay
+	Delay time.Duration
+	// * 每次重試等待時間的倍率，0 時為 2
+	Multiplier float64
+	// * 等待時間上限，0 為不限
+	MaxDelay time.Duration
+	// * 隨機浮動比例（0–1），避免同時重試
+	Jitter float64
+	// * 判斷錯誤是否重試，nil 時皆重試
+	Retryable func(error) bool
+}
+
+const defaultRetryDelay = time.Second
+
+type taskRetry struct {
+	task *task
+	at   time.Time
+}
+
+// * 依賴佇列項目，done 接收本次結果
+type taskWait struct {
+	Wait
+	done chan TaskResult
+	runs map[int64]int
+	// * 流程執行的 context，取消時結束等待
+	parent context.Context
+}
+
+type taskUpdate struct {
+	task     *task
+	schedule schedule
+}
+
+type taskResume struct {
+	task    *task
+	catchUp bool
+}
+
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type realClock struct{}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+// * 可手動推進的時間來源
+type FakeClock struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock  *FakeClock
+	when   time.Time
+	c      chan time.Time
+	fn     func()
+	active bool
+}
+
+type DayMatch int
+
+const (
+	// * POSIX：日與週皆有限制時，任一符合即觸發
+	DayOr DayMatch = iota
+	// * 日與週需同時符合
+	DayAnd
+)
+
+type DSTPolicy int
+
+const (
+	// * 跳過的時段於結束後補執行一次，重複的時段只執行一次
+	DSTOnce DSTPolicy = iota
+	// * 依牆上時間：跳過的時段不執行，重複的時段執行兩次
+	DSTWallClock
+)
+
 type cron struct {
 	mutex     sync.Mutex
 	wait      sync.WaitGroup
 	heap      taskHeap
+	tasks     map[int64]*task
+	workflows map[int64]*workflow
 	parser    parser
 	stop      chan struct{}
 	add       chan *task
-	remove    chan int64
-	removeAll chan struct{}
+	remove    chan *task
+	removeAll chan []*task
+	retry     chan taskRetry
+	resume    chan taskResume
+	update    chan taskUpdate
 	location  *time.Location
 	depend    *depend
 	next      int64
 	running   bool
+	paused    bool
 	logger    *slog.Logger
+	clock     Clock
+	misfire   Misfire
+	retention time.Duration
+	ctx       context.Context
+	cancel    context.CancelFunc
 }
 
 type depend struct {
 	mutex    sync.RWMutex
+	done     func(*task)
+	retry    func(*task, int, time.Duration) bool
 	wait     sync.WaitGroup
 	manager  *dependManager
 	running  bool
-	queue    chan Wait
+	queue    chan taskWait
 	stopChan chan struct{}
 	logger   *slog.Logger
+	clock    Clock
+	ctx      context.Context
 }
 
 type Wait struct {
@@ -64,15 +214,21 @@ const (
 type dependManager struct {
 	mutex   sync.RWMutex
 	list    map[int64]*task
-	waiting map[int64][]*task
+	waiting map[int64][]chan struct{}
+	clock   Clock
 }
 
 type task struct {
 	mutex       sync.RWMutex
 	ID          int64
+	name        string
 	description string
+	spec        string
 	schedule    schedule
-	action      func() error
+	action      func(context.Context) error
+	withError   bool
+	ctx         context.Context
+	cancel      context.CancelFunc
 	next        time.Time
 	prev        time.Time
 	enable      bool
@@ -85,8 +241,127 @@ type task struct {
 	result      *taskResult
 	startChan   chan struct{}
 	doneChan    chan taskResult
+	overlap     OverlapPolicy
+	running     int
+	queued      bool
+	queuedDone  chan TaskResult
+	skipped     int
+	queuedRuns  int
+	current     *taskRun
+	misfire     Misfire
+	missed      int
+	retry       Retry
+	retryAt     time.Time
+	attempt     int
+	runs        int
+	failures    int
+	paused      bool
+	pauseMissed bool
+	startAt     time.Time
+	endAt       time.Time
+	maxRuns     int
+	fired       int
+	jitter      time.Duration
+	base        time.Time
+	random      *rand.Rand
+	deferCheck  bool
+	consumed    map[int64]int
+}
+
+// * 單次執行，供 OverlapReplace 取消
+type taskRun struct {
+	cancel context.CancelFunc
+}
+
+// * 前一次執行尚未結束時的處理方式
+type OverlapPolicy int
+
+const (
+	// * 允許同時執行
+	OverlapAllow OverlapPolicy = iota
+	// * 略過本次執行
+	OverlapSkip
+	// * 保留一次待執行，於前一次結束後執行
+	OverlapQueue
+	// * 取消前一次執行並開始本次執行
+	OverlapReplace
+)
+
+// * 任務的唯讀快照
+type TaskInfo struct {
+	ID           int64
+	Name         string
+	Description  string
+	Spec         string
+	Next         time.Time
+	Prev         time.Time
+	State        int
+	Paused       bool
+	LastResult   *TaskResult
+	Runs         int
+	Failures     int
+	Skipped      int
+	Queued       int
+	Missed       int
+	Dependencies []Wait
+}
+
+// * 單次執行結果
+type TaskResult struct {
+	Status   int
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Error    error
+	Attempt  int
 }
 
+// * 依賴驗證錯誤：Dependency 不存在，或 Cycle 形成循環（首尾為同一任務）
+type DependencyError struct {
+	ID         int64
+	Dependency int64
+	Cycle      []int64
+}
+
+// * 多步驟流程，以單一排程執行
+type Workflow struct {
+	Name  string
+	Steps []Step
+}
+
+// * 流程步驟，After 為上游步驟名稱
+type Step struct {
+	Name    string
+	Action  func(context.Context) error
+	After   []string
+	State   WaitState
+	Delay   time.Duration
+	Timeout time.Duration
+}
+
+// * 單次流程執行結果，各步驟共用 ID
+type WorkflowRun struct {
+	ID     int64
+	Status int
+	Start  time.Time
+	End    time.Time
+	Steps  map[string]TaskResult
+}
+
+type workflow struct {
+	mutex sync.RWMutex
+	// * 逾時後前一次執行收尾期間，下一次執行於此等待
+	active sync.Mutex
+	name   string
+	steps  []*task
+	runs   int64
+	ctx    context.Context
+	last   *WorkflowRun
+}
+
+// * context 中的流程執行 ID
+type workflowRunKey struct{}
+
 type taskResult struct {
 	ID       int64
 	status   int
@@ -94,6 +369,7 @@ type taskResult struct {
 	end      time.Time
 	duration time.Duration
 	error    error
+	attempt  int
 }
 
 type taskState struct {
@@ -101,6 +377,7 @@ type taskState struct {
 	waiting []Wait
 	failed  *int64
 	error   error
+	runs    map[int64]int
 }
 
 type schedule interface {
@@ -108,23 +385,54 @@ type schedule interface {
 }
 
 type scheduleResult struct {
+	second,
 	minute,
 	hour,
 	dom,
 	month,
 	dow scheduleField
+	dayAnd   bool
+	dst      DSTPolicy
+	location *time.Location
+	// * 含 H 欄位，需以任務名稱重新解析
+	hashed bool
 }
 
 type scheduleField struct {
-	Value  int
+	// * 已排序的實際值
 	Values []int
 	All    bool
-	Step   int
+	// * 以 * 或 ? 開頭（如 */2），日與週的比對視為不限制
+	Star bool
+	// * 日期特殊字元（L、W、#），需依實際月曆判斷
+	Last        bool
+	LastWeekday bool
+	Weekday     []int
+	LastDow     []int
+	Nth         []nthWeekday
+}
+
+// * n#k：該月第 k 個週 n
+type nthWeekday struct {
+	dow int
+	n   int
 }
 
 type delayScheduleResult struct {
 	delay time.Duration
 }
 
+type atScheduleResult struct {
+	at time.Time
+}
+
 type taskHeap []*task
-type parser struct{}
+type parser struct {
+	seconds bool
+	dayAnd  bool
+	dst     DSTPolicy
+	// * H 欄位的雜湊來源
+	hash string
+	// * 驗證是否會觸發的基準時間來源，未設定時使用系統時間
+	clock Clock
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"container/heap"
+	"fmt"
+	"time"
+)
+
+// * 更新任務的排程與設定，保留 ID、依賴與執行紀錄；未傳入 WithDependencies 時沿用原依賴
+func (c *cron) Update(id int64, spec string, opts ...Option) error {
+	schedule, err := c.parser.parse(spec)
+	if err != nil {
+		return fmt.Errorf("failed to parse: %w", err)
+	}
+	if err := c.checkAt(schedule); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, isExist := c.tasks[id]
+	if !isExist {
+		return fmt.Errorf("task not found: %d", id)
+	}
+
+	// * 先套用至副本，避免錯誤時留下部分變更
+	entry.mutex.RLock()
+	updated := &task{
+		schedule:    schedule,
+		name:        entry.name,
+		description: entry.description,
+		delay:       entry.delay,
+		onDelay:     entry.onDelay,
+		overlap:     entry.overlap,
+		misfire:     entry.misfire,
+		retry:       entry.retry,
+		startAt:     entry.startAt,
+		endAt:       entry.endAt,
+		maxRuns:     entry.maxRuns,
+		jitter:      entry.jitter,
+	}
+	// * 沿用任務時區與夏令時間設定，可由 Option 覆寫；表達式的 CRON_TZ= 前綴仍優先
+	var location *time.Location
+	result, isCron := schedule.(*scheduleResult)
+	if old, ok := entry.schedule.(*scheduleResult); ok && isCron {
+		result.dst = old.dst
+		if prefix, _, _ := parseLocation(entry.spec); prefix == nil {
+			location = old.location
+		}
+	}
+	entry.mutex.RUnlock()
+
+	for _, opt := range opts {
+		if err := opt(updated); err != nil {
+			return fmt.Errorf("invalid option: %w", err)
+		}
+	}
+	if isCron && result.location == nil {
+		result.location = location
+	}
+	if err := updated.validateWindow(); err != nil {
+		return err
+	}
+	if len(updated.after) > 0 && !entry.withError {
+		return fmt.Errorf("need return value to get dependence support")
+	}
+	if updated.after != nil && !updated.deferCheck {
+		if err := c.checkDependencies(id, updated.after); err != nil {
+			return err
+		}
+	}
+	updated.ID, updated.spec = id, spec
+	if err := c.seed(updated); err != nil {
+		return fmt.Errorf("failed to parse: %w", err)
+	}
+	schedule = updated.schedule
+
+	c.depend.manager.mutex.RLock()
+	baseline := c.depend.manager.baseline(updated.after)
+	c.depend.manager.mutex.RUnlock()
+
+	entry.mutex.Lock()
+	entry.spec = spec
+	entry.name = updated.name
+	entry.description = updated.description
+	entry.delay = updated.delay
+	entry.onDelay = updated.onDelay
+	entry.overlap = updated.overlap
+	entry.misfire = updated.misfire
+	entry.retry = updated.retry
+	entry.startAt = updated.startAt
+	entry.endAt = updated.endAt
+	entry.maxRuns = updated.maxRuns
+	entry.jitter = updated.jitter
+	entry.random = updated.random
+	if updated.after != nil {
+		entry.after = updated.after
+		entry.wait = dependWait(updated.after)
+		// * 新加入的依賴僅採計之後的執行
+		for id, count := range baseline {
+			if _, isExist := entry.consumed[id]; !isExist {
+				entry.consumed[id] = count
+			}
+		}
+	}
+	entry.mutex.Unlock()
+
+	if c.running {
+		c.update <- taskUpdate{task: entry, schedule: schedule}
+		return nil
+	}
+
+	// * 未啟動時由 Start 重新計算下一次執行
+	entry.mutex.Lock()
+	entry.schedule = schedule
+	if entry.misfire.Threshold > 0 {
+		entry.next = entry.nextRun(c.clock.Now().In(c.location))
+	}
+	entry.mutex.Unlock()
+
+	for _, e := range c.heap {
+		if e == entry {
+			heap.Init(&c.heap)
+			return nil
+		}
+	}
+	c.heap = append(c.heap, entry)
+	heap.Init(&c.heap)
+	return nil
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func (e *DependencyError) Error() string {
+	if len(e.Cycle) > 0 {
+		path := make([]string, len(e.Cycle))
+		for i, id := range e.Cycle {
+			path[i] = fmt.Sprint(id)
+		}
+		return fmt.Sprintf("dependency cycle: %s", strings.Join(path, " -> "))
+	}
+	return fmt.Sprintf("dependence Task not found: %d (required by %d)", e.Dependency, e.ID)
+}
+
+// * 檢查所有任務的依賴，回傳所有不存在的依賴與循環
+func (c *cron) ValidateGraph() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	graph := c.graph()
+	ids := make([]int64, 0, len(graph))
+	for id := range graph {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	var errs []error
+	for _, id := range ids {
+		for _, after := range graph[id] {
+			if _, isExist := graph[after]; !isExist {
+				errs = append(errs, &DependencyError{ID: id, Dependency: after})
+			}
+		}
+	}
+
+	// * 深度優先搜尋，遇到仍在路徑上的任務即為循環
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[int64]int, len(graph))
+	var path []int64
+	var visit func(id int64)
+	visit = func(id int64) {
+		state[id] = visiting
+		path = append(path, id)
+		for _, after := range graph[id] {
+			switch state[after] {
+			case visiting:
+				for i, e := range path {
+					if e == after {
+						cycle := append(append([]int64{}, path[i:]...), after)
+						errs = append(errs, &DependencyError{ID: after, Cycle: cycle})
+						break
+					}
+				}
+			case unvisited:
+				if _, isExist := graph[after]; isExist {
+					visit(after)
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+	}
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// * 檢查任務的依賴存在且不形成循環，需持有 c.mutex
+func (c *cron) checkDependencies(id int64, after []Wait) error {
+	graph := c.graph()
+	graph[id] = nil
+	for _, w := range after {
+		if _, isExist := graph[w.ID]; !isExist {
+			return &DependencyError{ID: id, Dependency: w.ID}
+		}
+		graph[id] = append(graph[id], w.ID)
+	}
+
+	if cycle := findCycle(graph, id); cycle != nil {
+		return &DependencyError{ID: id, Cycle: cycle}
+	}
+	return nil
+}
+
+// * 自 id 出發，沿依賴回到 id 即為循環，回傳其路徑
+func findCycle(graph map[int64][]int64, id int64) []int64 {
+	seen := make(map[int64]bool)
+	var find func(current int64, path []int64) []int64
+	find = func(current int64, path []int64) []int64 {
+		for _, next := range graph[current] {
+			if next == id {
+				return append(path, next)
+			}
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			if cycle := find(next, append(path, next)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return find(id, []int64{id})
+}
+
+// * 任務 ID 對應其依賴 ID，需持有 c.mutex
+func (c *cron) graph() map[int64][]int64 {
+	graph := make(map[int64][]int64, len(c.tasks))
+	for id, t := range c.tasks {
+		t.mutex.RLock()
+		for _, w := range t.after {
+			graph[id] = append(graph[id], w.ID)
+		}
+		t.mutex.RUnlock()
+		if _, isExist := graph[id]; !isExist {
+			graph[id] = nil
+		}
+	}
+	return graph
+}
@@ -0,0 +1,227 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// * 新增流程，步驟依 After 串接，整體以單一排程觸發；回傳流程的任務 ID
+func (c *cron) AddWorkflow(spec string, w Workflow, opts ...Option) (int64, error) {
+	// * 同一流程的執行不重疊，步驟依賴僅對應本次執行；OverlapQueue 以外皆略過
+	probe := &task{}
+	for _, opt := range opts {
+		if err := opt(probe); err != nil {
+			return 0, fmt.Errorf("invalid option: %w", err)
+		}
+	}
+	overlap := OverlapSkip
+	if probe.overlap == OverlapQueue {
+		overlap = OverlapQueue
+	}
+
+	flow, err := c.newWorkflow(w)
+	if err != nil {
+		return 0, err
+	}
+
+	arg := []interface{}{WithName(w.Name)}
+	for _, opt := range opts {
+		arg = append(arg, opt)
+	}
+	arg = append(arg, WithOverlap(overlap))
+	id, err := c.Add(spec, flow.run(c), arg...)
+	if err != nil {
+		c.depend.manager.remove(flow.steps)
+		return 0, err
+	}
+
+	c.mutex.Lock()
+	c.workflows[id] = flow
+	c.mutex.Unlock()
+	return id, nil
+}
+
+// * 最近一次流程執行結果
+func (c *cron) LastWorkflowRun(id int64) (WorkflowRun, bool) {
+	c.mutex.Lock()
+	flow, isExist := c.workflows[id]
+	c.mutex.Unlock()
+	if !isExist {
+		return WorkflowRun{}, false
+	}
+
+	flow.mutex.RLock()
+	defer flow.mutex.RUnlock()
+
+	if flow.last == nil {
+		return WorkflowRun{}, false
+	}
+	run := *flow.last
+	run.Steps = make(map[string]TaskResult, len(flow.last.Steps))
+	for name, result := range flow.last.Steps {
+		run.Steps[name] = result
+	}
+	return run, true
+}
+
+// * 步驟執行中取得所屬流程執行的 ID
+func WorkflowRunID(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(workflowRunKey{}).(int64)
+	return id, ok
+}
+
+// * 檢查步驟並建立對應的任務，註冊至 dependManager
+func (c *cron) newWorkflow(w Workflow) (*workflow, error) {
+	if len(w.Steps) == 0 {
+		return nil, fmt.Errorf("workflow has no steps")
+	}
+
+	flow := &workflow{name: w.Name}
+	ids := make(map[string]int64, len(w.Steps))
+	for _, step := range w.Steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("workflow step name is empty")
+		}
+		if _, isExist := ids[step.Name]; isExist {
+			return nil, fmt.Errorf("duplicate workflow step: %s", step.Name)
+		}
+		if step.Action == nil {
+			return nil, fmt.Errorf("workflow step action is nil: %s", step.Name)
+		}
+		ids[step.Name] = atomic.AddInt64(&c.next, 1)
+	}
+
+	names := make(map[int64]string, len(ids))
+	graph := make(map[int64][]int64, len(ids))
+	for _, step := range w.Steps {
+		id := ids[step.Name]
+		names[id] = step.Name
+
+		var after []Wait
+		for _, name := range step.After {
+			afterID, isExist := ids[name]
+			if !isExist {
+				return nil, fmt.Errorf("workflow step %s depends on unknown step: %s", step.Name, name)
+			}
+			after = append(after, Wait{ID: afterID, Delay: step.Delay, State: step.State})
+			graph[id] = append(graph[id], afterID)
+		}
+
+		action := step.Action
+		ctx, cancel := context.WithCancel(context.Background())
+		flow.steps = append(flow.steps, &task{
+			ID:          id,
+			name:        step.Name,
+			description: w.Name + "/" + step.Name,
+			action: func(ctx context.Context) error {
+				// * 流程逾時或移除時一併取消步驟
+				id, runCtx := flow.current()
+				if err := runCtx.Err(); err != nil {
+					return err
+				}
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				stop := context.AfterFunc(runCtx, cancel)
+				defer stop()
+				return action(context.WithValue(ctx, workflowRunKey{}, id))
+			},
+			withError: true,
+			ctx:       ctx,
+			cancel:    cancel,
+			enable:    true,
+			state:     TaskPending,
+			delay:     step.Timeout,
+			after:     after,
+			wait:      dependWait(after),
+			attempt:   1,
+			consumed:  make(map[int64]int),
+		})
+	}
+
+	for id := range graph {
+		if cycle := findCycle(graph, id); cycle != nil {
+			path := make([]string, len(cycle))
+			for i, e := range cycle {
+				path[i] = names[e]
+			}
+			return nil, fmt.Errorf("workflow cycle: %s", strings.Join(path, " -> "))
+		}
+	}
+
+	for _, step := range flow.steps {
+		c.depend.manager.add(step)
+	}
+	return flow, nil
+}
+
+func (w *workflow) current() (int64, context.Context) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	return w.runs, w.ctx
+}
+
+// * 流程任務的執行內容：同時派送所有步驟，等待依賴的步驟不佔用 Worker
+func (w *workflow) run(c *cron) func(context.Context) error {
+	return func(ctx context.Context) error {
+		w.active.Lock()
+		defer w.active.Unlock()
+
+		w.mutex.Lock()
+		w.runs++
+		w.ctx = ctx
+		run := &WorkflowRun{
+			ID:    w.runs,
+			Start: c.clock.Now(),
+			Steps: make(map[string]TaskResult, len(w.steps)),
+		}
+		w.mutex.Unlock()
+
+		dones := make([]chan TaskResult, len(w.steps))
+		for i, step := range w.steps {
+			dones[i] = make(chan TaskResult, 1)
+			step.mutex.Lock()
+			step.running++
+			wait, waitState := step.wait, step.waitState
+			step.mutex.Unlock()
+			c.depend.addStepWait(ctx, step.ID, wait, waitState, dones[i])
+		}
+
+		// * 等待所有步驟結束，取消時亦同
+		var failed []string
+		for i, step := range w.steps {
+			result, ok := <-dones[i]
+			// * 排程停止時未執行
+			if !ok {
+				failed = append(failed, step.name)
+				continue
+			}
+			run.Steps[step.name] = result
+			if result.Status != TaskCompleted {
+				failed = append(failed, step.name)
+			}
+		}
+
+		run.End = c.clock.Now()
+		run.Status = TaskCompleted
+		var err error
+		if len(failed) > 0 {
+			run.Status = TaskFailed
+			err = fmt.Errorf("workflow steps failed: %s", strings.Join(failed, ", "))
+		}
+
+		w.mutex.Lock()
+		w.last = run
+		w.mutex.Unlock()
+
+		c.logger.Info(
+			"Workflow finished",
+			"name", w.name,
+			"run", run.ID,
+			"failed", len(failed),
+		)
+		return err
+	}
+}